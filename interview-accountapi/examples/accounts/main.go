@@ -5,11 +5,20 @@ import (
 	"crypto/rand"
 	"fmt"
 	"github.com/vslovik/form3/form3"
+	"github.com/vslovik/form3/form3/iban"
 	"log"
 	"strings"
 )
 
-var client = form3.NewClient(nil)
+var client = newClient()
+
+func newClient() *form3.Client {
+	c, err := form3.NewClientFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return c
+}
 
 func uuid() string {
 	b := make([]byte, 16)
@@ -26,6 +35,11 @@ func createAccount(id string, check bool) {
 	uid := uuid()
 	organizationId := strings.TrimSuffix(string(uid), "\n")
 
+	accountIBAN, err := iban.Generate("GB", "NWBK400300", "10000004")
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	attr := &form3.AccountCreateRequestAttributes{
 		BankID:                "400300",
 		BankIDCode:            "GBDSC",
@@ -34,7 +48,7 @@ func createAccount(id string, check bool) {
 		Country:               "GB",
 		AccountNumber:         "10000004",
 		CustomerID:            "234",
-		Iban:                  "GB28NWBK40030212764204",
+		Iban:                  accountIBAN,
 		AccountClassification: "Personal",
 	}
 
@@ -79,7 +93,7 @@ func createAccount(id string, check bool) {
 		if acc.Attributes.CustomerID != "234" {
 			log.Fatal(fmt.Sprintf("Invalid account CustomerID: %v\n", acc.Attributes.CustomerID))
 		}
-		if acc.Attributes.Iban != "GB28NWBK40030212764204" {
+		if acc.Attributes.Iban != accountIBAN {
 			log.Fatal(fmt.Sprintf("Invalid account Iban: %v\n", acc.Attributes.Iban))
 		}
 		if acc.Attributes.AccountClassification != "Personal" {
@@ -123,7 +137,7 @@ func createAccountBunch(number int) {
 	}
 }
 
-func getPage(page int, opt *form3.ListOptions) ([]*form3.Account, error) {
+func getPage(page int, opt *form3.AccountListOptions) ([]*form3.Account, error) {
 	opt.Page = page
 	accounts, _, _, err := client.Account.List(context.Background(), opt)
 	for _, elem := range accounts {
@@ -139,7 +153,7 @@ func getPage(page int, opt *form3.ListOptions) ([]*form3.Account, error) {
 // get all pages of results
 func getAllPages(perPage int) ([]*form3.Account, error) {
 	var allAccounts []*form3.Account
-	opt := &form3.ListOptions{PerPage: perPage}
+	opt := &form3.AccountListOptions{ListOptions: form3.ListOptions{PerPage: perPage}}
 	i := 0
 	for {
 		fmt.Printf("Retrieving Page %v of %v accounts...\n", i, opt.PerPage)
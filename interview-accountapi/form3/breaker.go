@@ -0,0 +1,229 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped with the host, for a request that is
+// not sent because the circuit breaker of its host is open.
+var ErrCircuitOpen = errors.New("form3: circuit breaker is open")
+
+// CircuitState is the state of the circuit breaker of a host.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through and counts their failures.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails requests with ErrCircuitOpen until the cool-down
+	// is over.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a few probe requests through. The circuit is
+	// closed if they succeed and opened again if one fails.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerPolicy specifies when the circuit breaker of a host opens
+// and closes. A request fails if it ends with a transport error, a 5xx
+// status code or the deadline of its context; requests canceled by their
+// context are not counted.
+type CircuitBreakerPolicy struct {
+	// Length of the windows in which the failures of a closed circuit are
+	// counted.
+	Window time.Duration
+
+	// Minimum number of requests in a window before the circuit may open.
+	MinRequests int
+
+	// Ratio of failed requests in a window, between 0 and 1, at which the
+	// circuit opens.
+	FailureRatio float64
+
+	// Time the circuit stays open before it becomes half-open.
+	CoolDown time.Duration
+
+	// Number of probe requests of a half-open circuit that must succeed to
+	// close it.
+	HalfOpenRequests int
+
+	// OnStateChange, if not nil, is called when the circuit of host changes
+	// state, e.g. to alert when it opens. It must not block.
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// DefaultCircuitBreakerPolicy is a circuit breaker policy suitable for most
+// callers.
+var DefaultCircuitBreakerPolicy = CircuitBreakerPolicy{
+	Window:           10 * time.Second,
+	MinRequests:      10,
+	FailureRatio:     0.5,
+	CoolDown:         30 * time.Second,
+	HalfOpenRequests: 1,
+}
+
+// WithCircuitBreaker makes the client stop sending requests to a host for a
+// while after too many of them failed, according to p, instead of piling up
+// requests waiting for their timeouts. Each host has its own circuit.
+func WithCircuitBreaker(p CircuitBreakerPolicy) ClientOption {
+	return func(c *Client) error {
+		if p.FailureRatio <= 0 || p.FailureRatio > 1 {
+			return errors.New("circuit breaker failure ratio must be above 0 and at most 1")
+		}
+		if p.Window <= 0 || p.CoolDown <= 0 {
+			return errors.New("circuit breaker window and cool-down must be positive")
+		}
+		if p.MinRequests < 1 || p.HalfOpenRequests < 1 {
+			return errors.New("circuit breaker request counts must be at least 1")
+		}
+		c.breakers = &circuitBreakers{policy: p, now: time.Now, circuits: make(map[string]*circuit)}
+		return nil
+	}
+}
+
+// outcome is how a request counts for a circuit breaker.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeIgnored
+)
+
+// requestOutcome classifies a request sent with ctx that ended with resp
+// and err.
+func requestOutcome(ctx context.Context, resp *http.Response, err error) outcome {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return outcomeIgnored
+	case ctx.Err() != nil:
+		// The API did not answer before the deadline, e.g. because it hangs.
+		return outcomeFailure
+	case err != nil || resp.StatusCode >= 500:
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+// circuitBreakers holds the circuits of the hosts of a client.
+type circuitBreakers struct {
+	policy CircuitBreakerPolicy
+	now    func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state       CircuitState
+	openedAt    time.Time
+	windowStart time.Time
+	requests    int // in the window when closed, probes sent when half-open
+	failures    int // in the window when closed
+	successes   int // of the probes when half-open
+}
+
+// allow reports, with an error wrapping ErrCircuitOpen, whether a request
+// to host may be sent.
+func (b *circuitBreakers) allow(host string) error {
+	b.mu.Lock()
+	c := b.circuit(host)
+	from := c.state
+	now := b.now()
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= b.policy.CoolDown {
+		c.state, c.requests, c.successes = CircuitHalfOpen, 0, 0
+	}
+	var err error
+	switch c.state {
+	case CircuitOpen:
+		err = fmt.Errorf("%w: %v", ErrCircuitOpen, host)
+	case CircuitHalfOpen:
+		if c.requests >= b.policy.HalfOpenRequests {
+			err = fmt.Errorf("%w: %v is half-open", ErrCircuitOpen, host)
+		} else {
+			c.requests++
+		}
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.changed(host, from, to)
+	return err
+}
+
+// record counts the outcome of a request to host that allow let through.
+func (b *circuitBreakers) record(host string, o outcome) {
+	b.mu.Lock()
+	c := b.circuit(host)
+	from := c.state
+	now := b.now()
+	switch c.state {
+	case CircuitClosed:
+		if o == outcomeIgnored {
+			break
+		}
+		if now.Sub(c.windowStart) >= b.policy.Window {
+			c.windowStart, c.requests, c.failures = now, 0, 0
+		}
+		c.requests++
+		if o == outcomeFailure {
+			c.failures++
+		}
+		if c.requests >= b.policy.MinRequests &&
+			float64(c.failures) >= b.policy.FailureRatio*float64(c.requests) {
+			c.state, c.openedAt = CircuitOpen, now
+		}
+	case CircuitHalfOpen:
+		switch o {
+		case outcomeFailure:
+			c.state, c.openedAt = CircuitOpen, now
+		case outcomeSuccess:
+			c.successes++
+			if c.successes >= b.policy.HalfOpenRequests {
+				c.state, c.windowStart, c.requests, c.failures = CircuitClosed, now, 0, 0
+			}
+		case outcomeIgnored:
+			// Let another probe through.
+			if c.requests > 0 {
+				c.requests--
+			}
+		}
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.changed(host, from, to)
+}
+
+// circuit returns the circuit of host, creating it if needed. b.mu is held.
+func (b *circuitBreakers) circuit(host string) *circuit {
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{windowStart: b.now()}
+		b.circuits[host] = c
+	}
+	return c
+}
+
+func (b *circuitBreakers) changed(host string, from, to CircuitState) {
+	if from != to && b.policy.OnStateChange != nil {
+		b.policy.OnStateChange(host, from, to)
+	}
+}
@@ -0,0 +1,201 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type stateChange struct {
+	host     string
+	from, to CircuitState
+}
+
+// testBreakers returns circuit breakers whose clock is advanced by hand,
+// recording their state changes.
+func testBreakers(p CircuitBreakerPolicy) (*circuitBreakers, *time.Time, *[]stateChange) {
+	now := time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC)
+	var changes []stateChange
+	p.OnStateChange = func(host string, from, to CircuitState) {
+		changes = append(changes, stateChange{host, from, to})
+	}
+	b := &circuitBreakers{policy: p, now: func() time.Time { return now }, circuits: make(map[string]*circuit)}
+	return b, &now, &changes
+}
+
+var testBreakerPolicy = CircuitBreakerPolicy{
+	Window:           time.Minute,
+	MinRequests:      4,
+	FailureRatio:     0.5,
+	CoolDown:         10 * time.Second,
+	HalfOpenRequests: 2,
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	b, now, changes := testBreakers(testBreakerPolicy)
+
+	// 2 failures of 4 requests open the circuit.
+	for _, o := range []outcome{outcomeSuccess, outcomeFailure, outcomeIgnored, outcomeSuccess, outcomeFailure} {
+		if err := b.allow("a"); err != nil {
+			t.Fatalf("allow of a closed circuit returned error: %v", err)
+		}
+		b.record("a", o)
+	}
+	if err := b.allow("a"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow of an open circuit returned %v, want %v", err, ErrCircuitOpen)
+	}
+	if err := b.allow("b"); err != nil {
+		t.Errorf("allow of another host returned error: %v", err)
+	}
+
+	// After the cool-down, 2 probes are let through.
+	*now = now.Add(10 * time.Second)
+	for i := 0; i < 2; i++ {
+		if err := b.allow("a"); err != nil {
+			t.Fatalf("allow of probe %d returned error: %v", i, err)
+		}
+	}
+	if err := b.allow("a"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow beyond the probes returned %v, want %v", err, ErrCircuitOpen)
+	}
+	b.record("a", outcomeSuccess)
+	b.record("a", outcomeSuccess)
+	if err := b.allow("a"); err != nil {
+		t.Errorf("allow after successful probes returned error: %v", err)
+	}
+
+	want := []stateChange{
+		{"a", CircuitClosed, CircuitOpen},
+		{"a", CircuitOpen, CircuitHalfOpen},
+		{"a", CircuitHalfOpen, CircuitClosed},
+	}
+	if fmt.Sprint(*changes) != fmt.Sprint(want) {
+		t.Errorf("State changes = %v, want %v", *changes, want)
+	}
+}
+
+func TestCircuitBreakers_ProbeFails(t *testing.T) {
+	b, now, _ := testBreakers(testBreakerPolicy)
+	for i := 0; i < 4; i++ {
+		b.allow("a")
+		b.record("a", outcomeFailure)
+	}
+
+	*now = now.Add(10 * time.Second)
+	b.allow("a")
+	b.record("a", outcomeFailure)
+	if err := b.allow("a"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow after a failed probe returned %v, want %v", err, ErrCircuitOpen)
+	}
+	if got := b.circuits["a"].state; got != CircuitOpen {
+		t.Errorf("State = %v, want open", got)
+	}
+}
+
+func TestCircuitBreakers_Window(t *testing.T) {
+	b, now, _ := testBreakers(testBreakerPolicy)
+	for i := 0; i < 3; i++ {
+		b.allow("a")
+		b.record("a", outcomeFailure)
+	}
+
+	// The failures of the previous window are forgotten.
+	*now = now.Add(time.Minute)
+	b.allow("a")
+	b.record("a", outcomeFailure)
+	if err := b.allow("a"); err != nil {
+		t.Errorf("allow in a new window returned error: %v", err)
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	p := testBreakerPolicy
+	p.MinRequests = 2
+	var opened []string
+	p.OnStateChange = func(host string, from, to CircuitState) {
+		if to == CircuitOpen {
+			opened = append(opened, host)
+		}
+	}
+	if err := WithCircuitBreaker(p)(client); err != nil {
+		t.Fatalf("WithCircuitBreaker returned error: %v", err)
+	}
+
+	calls := 0
+	mux.HandleFunc("/v1/organisation/accounts/a", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, _, _, err := client.Account.Fetch(ctx, "a"); !IsServerError(err) {
+			t.Fatalf("Account.Fetch returned %v, want a server error", err)
+		}
+	}
+	_, _, _, err := client.Account.Fetch(ctx, "a")
+	if !errors.Is(err, ErrCircuitOpen) || calls != 2 {
+		t.Errorf("Account.Fetch returned %v after %d calls, want %v after 2", err, calls, ErrCircuitOpen)
+	}
+	if len(opened) != 1 || opened[0] != client.BaseURL.Host {
+		t.Errorf("Opened circuits = %v, want %v", opened, client.BaseURL.Host)
+	}
+}
+
+func TestWithCircuitBreaker_Deadline(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	p := testBreakerPolicy
+	p.MinRequests = 2
+	if err := WithCircuitBreaker(p)(client); err != nil {
+		t.Fatalf("WithCircuitBreaker returned error: %v", err)
+	}
+
+	// The API hangs until the client gives up.
+	mux.HandleFunc("/v1/organisation/accounts/a", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, _, _, err := client.Account.Fetch(ctx, "a")
+		cancel()
+		if err != context.DeadlineExceeded {
+			t.Fatalf("Account.Fetch returned %v, want %v", err, context.DeadlineExceeded)
+		}
+	}
+	if _, _, _, err := client.Account.Fetch(context.Background(), "a"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Account.Fetch after timeouts returned %v, want %v", err, ErrCircuitOpen)
+	}
+}
+
+func TestRequestOutcome_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if o := requestOutcome(ctx, nil, context.Canceled); o != outcomeIgnored {
+		t.Errorf("requestOutcome of a canceled request = %v, want %v", o, outcomeIgnored)
+	}
+}
+
+func TestWithCircuitBreaker_Invalid(t *testing.T) {
+	invalid := []func(*CircuitBreakerPolicy){
+		func(p *CircuitBreakerPolicy) { p.FailureRatio = 0 },
+		func(p *CircuitBreakerPolicy) { p.FailureRatio = 1.5 },
+		func(p *CircuitBreakerPolicy) { p.Window = 0 },
+		func(p *CircuitBreakerPolicy) { p.CoolDown = -time.Second },
+		func(p *CircuitBreakerPolicy) { p.MinRequests = 0 },
+		func(p *CircuitBreakerPolicy) { p.HalfOpenRequests = 0 },
+	}
+	for i, f := range invalid {
+		p := DefaultCircuitBreakerPolicy
+		f(&p)
+		if _, err := NewClient(WithCircuitBreaker(p)); err == nil {
+			t.Errorf("NewClient with invalid policy %d returned no error", i)
+		}
+	}
+}
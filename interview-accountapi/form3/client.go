@@ -11,37 +11,91 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/vslovik/form3/httpsig"
 )
 
-const defaultBaseURL = "http://accountapi:8080/"
+const (
+	defaultBaseURL   = "http://accountapi:8080/"
+	defaultUserAgent = "form3-go"
+
+	// Amount of an unread response body that is drained before closing it.
+	maxBodySlurpSize = 2 << 10
+)
 
 type Client struct {
 	client *http.Client
 
 	BaseURL *url.URL
 
+	// User agent used when communicating with the Form3 API.
+	UserAgent string
+
+	timeout     time.Duration
+	headers     http.Header      // Default headers sent with every request.
+	retryPolicy *RetryPolicy     // Retries are disabled if nil.
+	signer      *httpsig.Signer  // Requests are not signed if nil.
+	tokens      *tokenSource     // OAuth2 is not used if nil.
+	limiter     *rateLimiter     // Requests are not rate limited if nil.
+	breakers    *circuitBreakers // Circuit breaking is disabled if nil.
+	middleware  []Middleware     // Outermost first.
+
+	validateRequests bool // Validate requests before sending them.
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
-	// Services used for talking to account part of the Form3 API.
-	Account *AccountService
+	// Services used for talking to different parts of the Form3 API.
+	Account      *AccountService
+	Organisation *OrganisationService
+	Payment      *PaymentService
+	Return       *ReturnService
+	Reversal     *ReversalService
+	Recall       *RecallService
+	Subscription *SubscriptionService
 }
 
 type service struct {
 	client *Client
 }
 
-// NewClient returns a new Form3 API client. If a nil httpClient is
-// provided, a new http.Client will be used.
-func NewClient(httpClient *http.Client) *Client {
-	if httpClient == nil {
-		httpClient = &http.Client{}
-	}
+// NewClient returns a new Form3 API client configured with opts. Without
+// options the client talks to defaultBaseURL using a new http.Client.
+func NewClient(opts ...ClientOption) (*Client, error) {
 	baseURL, _ := url.Parse(defaultBaseURL)
 
-	c := &Client{client: httpClient, BaseURL: baseURL}
+	c := &Client{
+		client:    &http.Client{},
+		BaseURL:   baseURL,
+		UserAgent: defaultUserAgent,
+		headers:   make(http.Header),
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.tokens != nil && c.signer != nil {
+		return nil, errors.New("WithClientCredentials and WithSigning cannot be combined: both set the Authorization header")
+	}
+	if c.timeout > 0 {
+		hc := *c.client
+		hc.Timeout = c.timeout
+		c.client = &hc
+	}
+
 	c.common.client = c
 	c.Account = (*AccountService)(&c.common)
-	return c
+	c.Organisation = (*OrganisationService)(&c.common)
+	c.Payment = (*PaymentService)(&c.common)
+	c.Return = (*ReturnService)(&c.common)
+	c.Reversal = (*ReversalService)(&c.common)
+	c.Recall = (*RecallService)(&c.common)
+	c.Subscription = (*SubscriptionService)(&c.common)
+	return c, nil
 }
 
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
@@ -74,14 +128,22 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		return nil, err
 	}
 
+	for k, v := range c.headers {
+		req.Header[k] = append([]string(nil), v...)
+	}
+
 	if method != "DELETE" {
 		req.Header.Set("Accept", "application/vnd.api+json")
 	}
 
-	if method == "POST" {
+	if method == "POST" || method == "PATCH" {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
 	return req, nil
 }
 
@@ -89,7 +151,8 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 // JSON decoded and stored in the value pointed to by v, or returned as an
 // error if an API error has occurred. If v implements the io.Writer
 // interface, the raw response body will be written to v, without attempting to
-// first decode it.
+// first decode it. The request is sent through the middleware of the client,
+// see Use.
 //
 // The provided ctx must be non-nil, if it is nil an error is returned. If it is canceled or times out,
 // ctx.Err() will be returned.
@@ -99,7 +162,11 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	}
 	req = withContext(ctx, req)
 
-	resp, err := c.client.Do(req)
+	start := time.Now()
+	resp, err := c.roundTrip(req)
+	if err == nil && resp == nil {
+		err = errors.New("middleware returned neither a response nor an error")
+	}
 	if err != nil {
 		// If we got an error, and the context has been canceled,
 		// the context's error is probably more useful.
@@ -127,7 +194,6 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 		// the body so if it's small the underlying TCP connection will be
 		// re-used. No need to check for errors: if it fails, the Transport
 		// won't reuse it anyway.
-		const maxBodySlurpSize = 2 << 10
 		if resp.ContentLength == -1 || resp.ContentLength <= maxBodySlurpSize {
 			_, err := io.CopyN(ioutil.Discard, resp.Body, maxBodySlurpSize)
 			if err != nil {
@@ -140,6 +206,7 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	}()
 
 	response := newResponse(resp)
+	response.Duration = time.Since(start)
 
 	err = CheckResponse(resp)
 	if err != nil {
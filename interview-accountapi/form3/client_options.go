@@ -0,0 +1,109 @@
+package form3
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvBaseURL   = "FORM3_BASE_URL"
+	EnvUserAgent = "FORM3_USER_AGENT"
+	EnvTimeout   = "FORM3_TIMEOUT"
+)
+
+// ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client) error
+
+// WithBaseURL sets the base URL of the Form3 API. A trailing slash is added
+// if it is missing.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL %q: %v", baseURL, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q: scheme and host are required", baseURL)
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http.Client used to send requests. If it is nil,
+// a new http.Client is used.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) error {
+		if httpClient == nil {
+			httpClient = &http.Client{}
+		}
+		c.client = httpClient
+		return nil
+	}
+}
+
+// WithTimeout sets the time limit for each request made by the client,
+// including connection time, redirects and reading the response body.
+// The http.Client passed with WithHTTPClient is not modified, a copy is used
+// instead.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout must be non-negative, got %v", timeout)
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request. It may be used several
+// times to add several headers or several values of the same header.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) error {
+		c.headers.Add(key, value)
+		return nil
+	}
+}
+
+// NewClientFromEnv returns a new Form3 API client configured from the
+// environment:
+//
+//	FORM3_BASE_URL    base URL of the Form3 API
+//	FORM3_USER_AGENT  User-Agent header sent with every request
+//	FORM3_TIMEOUT     request timeout, as parsed by time.ParseDuration
+//
+// Unset variables leave the defaults in place. opts are applied after the
+// environment, so they take precedence over it.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	var envOpts []ClientOption
+	if v := os.Getenv(EnvBaseURL); v != "" {
+		envOpts = append(envOpts, WithBaseURL(v))
+	}
+	if v := os.Getenv(EnvUserAgent); v != "" {
+		envOpts = append(envOpts, WithUserAgent(v))
+	}
+	if v := os.Getenv(EnvTimeout); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", EnvTimeout, err)
+		}
+		envOpts = append(envOpts, WithTimeout(d))
+	}
+	return NewClient(append(envOpts, opts...)...)
+}
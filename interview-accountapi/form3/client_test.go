@@ -0,0 +1,90 @@
+package form3
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewClient_Defaults(t *testing.T) {
+	c, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if got, want := c.BaseURL.String(), defaultBaseURL; got != want {
+		t.Errorf("NewClient BaseURL is %v, want %v", got, want)
+	}
+	if got, want := c.UserAgent, defaultUserAgent; got != want {
+		t.Errorf("NewClient UserAgent is %v, want %v", got, want)
+	}
+	if c.Account == nil {
+		t.Errorf("NewClient Account service is nil")
+	}
+}
+
+func TestNewClient_Options(t *testing.T) {
+	hc := &http.Client{}
+	c, err := NewClient(
+		WithBaseURL("http://localhost:8080"),
+		WithUserAgent("ua"),
+		WithHTTPClient(hc),
+		WithTimeout(5*time.Second),
+		WithHeader("X-Tenant", "t1"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if got, want := c.BaseURL.String(), "http://localhost:8080/"; got != want {
+		t.Errorf("NewClient BaseURL is %v, want %v", got, want)
+	}
+	if got, want := c.client.Timeout, 5*time.Second; got != want {
+		t.Errorf("NewClient timeout is %v, want %v", got, want)
+	}
+	if hc.Timeout != 0 {
+		t.Errorf("WithTimeout modified the provided http.Client")
+	}
+
+	req, err := c.NewRequest("GET", "v1/organisation/accounts", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	testHeader(t, req, "User-Agent", "ua")
+	testHeader(t, req, "X-Tenant", "t1")
+}
+
+func TestNewClient_InvalidBaseURL(t *testing.T) {
+	for _, u := range []string{"localhost", "://x", ""} {
+		if _, err := NewClient(WithBaseURL(u)); err == nil {
+			t.Errorf("NewClient(WithBaseURL(%q)) returned no error", u)
+		}
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	defer os.Unsetenv(EnvBaseURL)
+	defer os.Unsetenv(EnvUserAgent)
+	defer os.Unsetenv(EnvTimeout)
+	os.Setenv(EnvBaseURL, "http://localhost:8080/")
+	os.Setenv(EnvUserAgent, "env-agent")
+	os.Setenv(EnvTimeout, "2s")
+
+	c, err := NewClientFromEnv(WithUserAgent("explicit"))
+	if err != nil {
+		t.Fatalf("NewClientFromEnv returned error: %v", err)
+	}
+	if got, want := c.BaseURL.String(), "http://localhost:8080/"; got != want {
+		t.Errorf("NewClientFromEnv BaseURL is %v, want %v", got, want)
+	}
+	if got, want := c.UserAgent, "explicit"; got != want {
+		t.Errorf("NewClientFromEnv UserAgent is %v, want %v", got, want)
+	}
+	if got, want := c.client.Timeout, 2*time.Second; got != want {
+		t.Errorf("NewClientFromEnv timeout is %v, want %v", got, want)
+	}
+
+	os.Setenv(EnvTimeout, "soon")
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Errorf("NewClientFromEnv returned no error for invalid %s", EnvTimeout)
+	}
+}
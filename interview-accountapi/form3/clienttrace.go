@@ -0,0 +1,27 @@
+package form3
+
+import "context"
+
+// ClientTrace is a set of hooks run during a call of Client.Do, like
+// httptrace.ClientTrace for an http.Client. Any hook may be nil. A
+// middleware may add one to the context of a request to observe what
+// happens inside the chain, e.g. to count retries.
+type ClientTrace struct {
+	// Attempt is called before every attempt to send the request, counted
+	// from 1, once it got past the rate limiter and the circuit breaker.
+	Attempt func(n int)
+}
+
+type clientTraceKey struct{}
+
+// WithClientTrace returns a copy of ctx carrying trace. The requests sent
+// with the returned context run its hooks.
+func WithClientTrace(ctx context.Context, trace *ClientTrace) context.Context {
+	return context.WithValue(ctx, clientTraceKey{}, trace)
+}
+
+// ContextClientTrace returns the ClientTrace carried by ctx, or nil.
+func ContextClientTrace(ctx context.Context) *ClientTrace {
+	trace, _ := ctx.Value(clientTraceKey{}).(*ClientTrace)
+	return trace
+}
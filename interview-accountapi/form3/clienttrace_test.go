@@ -0,0 +1,33 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClientTrace_Attempt(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retryPolicy = &testRetryPolicy
+
+	mux.HandleFunc("/v1/organisation/accounts/a", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	var attempts []int
+	ctx := WithClientTrace(context.Background(), &ClientTrace{Attempt: func(n int) {
+		attempts = append(attempts, n)
+	}})
+	if _, _, _, err := client.Account.Fetch(ctx, "a"); !IsServerError(err) {
+		t.Fatalf("Account.Fetch returned %v, want a server error", err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("Attempts = %v, want %v", attempts, want)
+	}
+
+	if ContextClientTrace(context.Background()) != nil {
+		t.Errorf("ContextClientTrace of a context without trace is not nil")
+	}
+}
@@ -0,0 +1,215 @@
+package form3
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/vslovik/form3/iban"
+)
+
+// FieldRule tells whether an account attribute is optional, required or
+// forbidden in a country.
+type FieldRule int
+
+const (
+	FieldOptional FieldRule = iota
+	FieldRequired
+	FieldForbidden
+)
+
+// CountryRules holds the account creation rules of a country.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-accounts-create-data-model
+type CountryRules struct {
+	Country string // ISO 3166-1 alpha-2 country code
+
+	BankID       FieldRule
+	BankIDFormat *regexp.Regexp // checked when a bank ID is given
+	BankIDCode   string         // required bank ID code, empty if the country has none
+
+	BIC FieldRule
+
+	AccountNumberFormat *regexp.Regexp // checked when an account number is given
+
+	// IBAN is FieldForbidden in countries that do not use IBANs. In the
+	// others, IBANGeneration tells whether the IBAN can be generated from
+	// the bank ID and account number with GenerateIBAN, which requires
+	// iban.Generate to support the country.
+	IBAN           FieldRule
+	IBANGeneration bool
+
+	BaseCurrency string // default base currency
+
+	// AccountCheck, if set, checks a bank ID and account number pair given
+	// together, e.g. with the UK modulus check of iban.ModulusChecker.
+	AccountCheck func(bankID, accountNumber string) error
+}
+
+var ukModulus struct {
+	sync.RWMutex
+	checker *iban.ModulusChecker
+}
+
+// RegisterUKModulusChecker makes the GB rules check sort code and account
+// number pairs with c, typically built from the VocaLink weight table. The
+// table is not bundled, so GB pairs are not checked until a checker is
+// registered. A nil c removes the checker.
+func RegisterUKModulusChecker(c *iban.ModulusChecker) {
+	ukModulus.Lock()
+	defer ukModulus.Unlock()
+	ukModulus.checker = c
+}
+
+// checkUKModulus is the AccountCheck of the GB rules.
+func checkUKModulus(sortCode, accountNumber string) error {
+	ukModulus.RLock()
+	c := ukModulus.checker
+	ukModulus.RUnlock()
+	if c == nil {
+		return nil
+	}
+	return c.Check(sortCode, accountNumber)
+}
+
+var countryRules = struct {
+	sync.RWMutex
+	m map[string]*CountryRules
+}{m: make(map[string]*CountryRules)}
+
+// RegisterCountryRules registers the rules of r.Country, replacing the rules
+// registered before for that country.
+func RegisterCountryRules(r *CountryRules) {
+	countryRules.Lock()
+	defer countryRules.Unlock()
+	countryRules.m[r.Country] = r
+}
+
+// LookupCountryRules returns the rules registered for country.
+func LookupCountryRules(country string) (*CountryRules, bool) {
+	countryRules.RLock()
+	defer countryRules.RUnlock()
+	r, ok := countryRules.m[country]
+	return r, ok
+}
+
+// check adds an error to errs for every attribute of a breaking the rules.
+func (r *CountryRules) check(a *AccountCreateRequestAttributes, errs *FieldErrors) {
+	checkField(errs, "bank_id", a.BankID, r.BankID, r.Country)
+	if a.BankID != "" && r.BankIDFormat != nil && !r.BankIDFormat.MatchString(a.BankID) {
+		errs.add("bank_id", a.BankID, fmt.Sprintf("does not match the %v format %v", r.Country, r.BankIDFormat))
+	}
+
+	switch {
+	case r.BankIDCode == "" && a.BankIDCode != "":
+		errs.add("bank_id_code", a.BankIDCode, fmt.Sprintf("is not allowed for %v", r.Country))
+	case r.BankIDCode != "" && a.BankIDCode != r.BankIDCode:
+		errs.add("bank_id_code", a.BankIDCode, fmt.Sprintf("must be %v for %v", r.BankIDCode, r.Country))
+	}
+
+	checkField(errs, "bic", a.Bic, r.BIC, r.Country)
+
+	if a.AccountNumber != "" && r.AccountNumberFormat != nil && !r.AccountNumberFormat.MatchString(a.AccountNumber) {
+		errs.add("account_number", a.AccountNumber, fmt.Sprintf("does not match the %v format %v", r.Country, r.AccountNumberFormat))
+	}
+
+	checkField(errs, "iban", a.Iban, r.IBAN, r.Country)
+
+	if r.AccountCheck != nil && a.BankID != "" && a.AccountNumber != "" {
+		if err := r.AccountCheck(a.BankID, a.AccountNumber); err != nil {
+			errs.add("account_number", a.AccountNumber, err.Error())
+		}
+	}
+}
+
+func checkField(errs *FieldErrors, field, value string, rule FieldRule, country string) {
+	switch {
+	case rule == FieldRequired && value == "":
+		errs.add(field, value, fmt.Sprintf("is required for %v", country))
+	case rule == FieldForbidden && value != "":
+		errs.add(field, value, fmt.Sprintf("is not allowed for %v", country))
+	}
+}
+
+// ApplyCountryDefaults fills the bank ID code and the base currency from
+// the rules of a.Country, if they are not set. It fails if no rules are
+// registered for the country.
+func (a *AccountCreateRequestAttributes) ApplyCountryDefaults() error {
+	r, ok := LookupCountryRules(a.Country)
+	if !ok {
+		return fmt.Errorf("no account rules registered for country %q", a.Country)
+	}
+	if a.BankIDCode == "" {
+		a.BankIDCode = r.BankIDCode
+	}
+	if a.BaseCurrency == "" {
+		a.BaseCurrency = r.BaseCurrency
+	}
+	return nil
+}
+
+// GenerateIBAN sets a.Iban to the IBAN built from the bank ID and account
+// number, in the countries whose rules have IBANGeneration set. For GB, IE
+// and NL the bank code is taken from the BIC.
+func (a *AccountCreateRequestAttributes) GenerateIBAN() error {
+	r, ok := LookupCountryRules(a.Country)
+	if !ok || !r.IBANGeneration {
+		return fmt.Errorf("IBAN generation is not supported for country %q", a.Country)
+	}
+	bankID := a.BankID
+	switch a.Country {
+	case "GB", "IE", "NL":
+		if len(a.Bic) < 4 {
+			return fmt.Errorf("a BIC is required to generate %v IBANs", a.Country)
+		}
+		bankID = a.Bic[:4] + a.BankID
+	}
+	s, err := iban.Generate(a.Country, bankID, a.AccountNumber)
+	if err != nil {
+		return err
+	}
+	a.Iban = s
+	return nil
+}
+
+func init() {
+	digits := func(n string) *regexp.Regexp { return regexp.MustCompile(`^[0-9]{` + n + `}$`) }
+	alnum := func(n string) *regexp.Regexp { return regexp.MustCompile(`^[0-9A-Z]{` + n + `}$`) }
+
+	for _, r := range []*CountryRules{
+		{Country: "GB", BankID: FieldRequired, BankIDFormat: digits("6"), BankIDCode: "GBDSC", BIC: FieldRequired,
+			AccountNumberFormat: digits("8"), IBANGeneration: true, BaseCurrency: "GBP", AccountCheck: checkUKModulus},
+		{Country: "AU", BankIDFormat: digits("6"), BankIDCode: "AUBSB", BIC: FieldRequired,
+			AccountNumberFormat: regexp.MustCompile(`^[1-9][0-9]{5,9}$`), IBAN: FieldForbidden, BaseCurrency: "AUD"},
+		{Country: "BE", BankID: FieldRequired, BankIDFormat: digits("3"), BankIDCode: "BE",
+			AccountNumberFormat: digits("7"), IBANGeneration: true, BaseCurrency: "EUR"},
+		{Country: "CA", BankIDFormat: regexp.MustCompile(`^0[0-9]{8}$`), BankIDCode: "CACPA", BIC: FieldRequired,
+			AccountNumberFormat: digits("7,12"), IBAN: FieldForbidden, BaseCurrency: "CAD"},
+		{Country: "FR", BankID: FieldRequired, BankIDFormat: alnum("10"), BankIDCode: "FR",
+			AccountNumberFormat: alnum("10"), BaseCurrency: "EUR"},
+		{Country: "DE", BankID: FieldRequired, BankIDFormat: digits("8"), BankIDCode: "DEBLZ",
+			AccountNumberFormat: digits("7"), IBANGeneration: true, BaseCurrency: "EUR"},
+		{Country: "GR", BankID: FieldRequired, BankIDFormat: digits("7"), BankIDCode: "GRBIC",
+			AccountNumberFormat: digits("16"), IBANGeneration: true, BaseCurrency: "EUR"},
+		{Country: "HK", BankIDFormat: digits("3"), BankIDCode: "HKNCC", BIC: FieldRequired,
+			AccountNumberFormat: digits("9,12"), IBAN: FieldForbidden, BaseCurrency: "HKD"},
+		{Country: "IT", BankID: FieldRequired, BankIDFormat: alnum("10,11"), BankIDCode: "ITNCC",
+			AccountNumberFormat: alnum("12"), BaseCurrency: "EUR"},
+		{Country: "LU", BankID: FieldRequired, BankIDFormat: digits("3"), BankIDCode: "LULUX",
+			AccountNumberFormat: alnum("13"), IBANGeneration: true, BaseCurrency: "EUR"},
+		{Country: "NL", BankID: FieldForbidden, BIC: FieldRequired,
+			AccountNumberFormat: digits("10"), IBANGeneration: true, BaseCurrency: "EUR"},
+		{Country: "PL", BankID: FieldRequired, BankIDFormat: digits("8"), BankIDCode: "PLKNR",
+			AccountNumberFormat: digits("16"), IBANGeneration: true, BaseCurrency: "PLN"},
+		{Country: "PT", BankID: FieldRequired, BankIDFormat: digits("8"), BankIDCode: "PTNCC",
+			AccountNumberFormat: digits("11"), IBANGeneration: true, BaseCurrency: "EUR"},
+		{Country: "ES", BankID: FieldRequired, BankIDFormat: digits("8"), BankIDCode: "ESNCC",
+			AccountNumberFormat: digits("10"), IBANGeneration: true, BaseCurrency: "EUR"},
+		{Country: "CH", BankID: FieldRequired, BankIDFormat: digits("5"), BankIDCode: "CHBCC",
+			AccountNumberFormat: alnum("12"), IBANGeneration: true, BaseCurrency: "CHF"},
+		{Country: "US", BankID: FieldRequired, BankIDFormat: digits("9"), BankIDCode: "USABA", BIC: FieldRequired,
+			AccountNumberFormat: digits("6,17"), IBAN: FieldForbidden, BaseCurrency: "USD"},
+	} {
+		RegisterCountryRules(r)
+	}
+}
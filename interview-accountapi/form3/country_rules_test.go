@@ -0,0 +1,159 @@
+package form3
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vslovik/form3/iban"
+)
+
+func TestCountryRules(t *testing.T) {
+	tests := []struct {
+		attr  *AccountCreateRequestAttributes
+		field string // invalid field, empty if valid
+	}{
+		{&AccountCreateRequestAttributes{Country: "GB", BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", AccountNumber: "41426819"}, ""},
+		{&AccountCreateRequestAttributes{Country: "GB", BankIDCode: "GBDSC", Bic: "NWBKGB22"}, "bank_id"},
+		{&AccountCreateRequestAttributes{Country: "GB", BankID: "4003", BankIDCode: "GBDSC", Bic: "NWBKGB22"}, "bank_id"},
+		{&AccountCreateRequestAttributes{Country: "GB", BankID: "400300", BankIDCode: "AUBSB", Bic: "NWBKGB22"}, "bank_id_code"},
+		{&AccountCreateRequestAttributes{Country: "GB", BankID: "400300", BankIDCode: "GBDSC"}, "bic"},
+		{&AccountCreateRequestAttributes{Country: "GB", BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", AccountNumber: "123"}, "account_number"},
+		{&AccountCreateRequestAttributes{Country: "AU", BankIDCode: "AUBSB", Bic: "NAUBAU33"}, ""},
+		{&AccountCreateRequestAttributes{Country: "AU", BankIDCode: "AUBSB", Bic: "NAUBAU33", AccountNumber: "0123456"}, "account_number"},
+		{&AccountCreateRequestAttributes{Country: "AU", BankIDCode: "AUBSB", Bic: "NAUBAU33", Iban: "GB71NWBK40030212764204"}, "iban"},
+		{&AccountCreateRequestAttributes{Country: "BE", BankID: "123", BankIDCode: "BE"}, ""},
+		{&AccountCreateRequestAttributes{Country: "BE", BankID: "1234", BankIDCode: "BE"}, "bank_id"},
+		{&AccountCreateRequestAttributes{Country: "CA", BankIDCode: "CACPA", Bic: "ROYCCAT2", BankID: "123456789"}, "bank_id"},
+		{&AccountCreateRequestAttributes{Country: "NL", Bic: "ABNANL2A", BankID: "123"}, "bank_id"},
+		{&AccountCreateRequestAttributes{Country: "NL", Bic: "ABNANL2A", BankIDCode: "NL"}, "bank_id_code"},
+		{&AccountCreateRequestAttributes{Country: "JP"}, ""},
+	}
+	for _, tt := range tests {
+		err := tt.attr.Validate()
+		if tt.field == "" {
+			if err != nil {
+				t.Errorf("Validate(%+v) returned error: %v", tt.attr, err)
+			}
+			continue
+		}
+		errs, _ := err.(FieldErrors)
+		if len(errs) != 1 || errs[0].Field != tt.field {
+			t.Errorf("Validate(%+v) returned %v, want invalid %v", tt.attr, err, tt.field)
+		}
+	}
+}
+
+func TestRegisterCountryRules(t *testing.T) {
+	old, _ := LookupCountryRules("JP")
+	defer func() {
+		if old == nil {
+			countryRules.Lock()
+			delete(countryRules.m, "JP")
+			countryRules.Unlock()
+		}
+	}()
+
+	RegisterCountryRules(&CountryRules{
+		Country:             "JP",
+		BankID:              FieldRequired,
+		BankIDCode:          "JPZGN",
+		AccountNumberFormat: regexp.MustCompile(`^[0-9]{7}$`),
+		IBAN:                FieldForbidden,
+		BaseCurrency:        "JPY",
+	})
+
+	a := &AccountCreateRequestAttributes{Country: "JP", BankID: "0001"}
+	if err := a.ApplyCountryDefaults(); err != nil {
+		t.Fatalf("ApplyCountryDefaults returned error: %v", err)
+	}
+	if a.BankIDCode != "JPZGN" || a.BaseCurrency != "JPY" {
+		t.Errorf("ApplyCountryDefaults set %v and %v, want JPZGN and JPY", a.BankIDCode, a.BaseCurrency)
+	}
+	if err := a.Validate(); err != nil {
+		t.Errorf("Validate returned error: %v", err)
+	}
+	a.AccountNumber = "12"
+	if err := a.Validate(); err == nil {
+		t.Errorf("Validate with an invalid JP account number returned no error")
+	}
+}
+
+func TestApplyCountryDefaults(t *testing.T) {
+	a := &AccountCreateRequestAttributes{Country: "GB", BaseCurrency: "EUR"}
+	if err := a.ApplyCountryDefaults(); err != nil {
+		t.Fatalf("ApplyCountryDefaults returned error: %v", err)
+	}
+	if a.BankIDCode != "GBDSC" {
+		t.Errorf("ApplyCountryDefaults set bank id code %v, want GBDSC", a.BankIDCode)
+	}
+	if a.BaseCurrency != "EUR" {
+		t.Errorf("ApplyCountryDefaults overwrote base currency with %v", a.BaseCurrency)
+	}
+
+	if err := (&AccountCreateRequestAttributes{Country: "XX"}).ApplyCountryDefaults(); err == nil {
+		t.Errorf("ApplyCountryDefaults for an unknown country returned no error")
+	}
+}
+
+func TestGenerateIBAN(t *testing.T) {
+	a := &AccountCreateRequestAttributes{Country: "GB", BankID: "400300", Bic: "NWBKGB22", AccountNumber: "10000004"}
+	if err := a.GenerateIBAN(); err != nil {
+		t.Fatalf("GenerateIBAN returned error: %v", err)
+	}
+	if want := "GB31NWBK40030010000004"; a.Iban != want {
+		t.Errorf("GenerateIBAN set %v, want %v", a.Iban, want)
+	}
+
+	a = &AccountCreateRequestAttributes{Country: "DE", BankID: "37040044", AccountNumber: "532013000"}
+	if err := a.GenerateIBAN(); err != nil {
+		t.Fatalf("GenerateIBAN returned error: %v", err)
+	}
+	if want := "DE89370400440532013000"; a.Iban != want {
+		t.Errorf("GenerateIBAN set %v, want %v", a.Iban, want)
+	}
+
+	for _, a := range []*AccountCreateRequestAttributes{
+		{Country: "AU", BankID: "123456", AccountNumber: "12345678"},
+		{Country: "GB", BankID: "400300", AccountNumber: "10000004"},
+	} {
+		if err := a.GenerateIBAN(); err == nil {
+			t.Errorf("GenerateIBAN(%v) returned no error", a.Country)
+		}
+	}
+}
+
+func TestCountryRules_AccountCheck(t *testing.T) {
+	weights, err := iban.ParseModulusWeights(strings.NewReader(
+		"089000 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7 1"))
+	if err != nil {
+		t.Fatalf("ParseModulusWeights returned error: %v", err)
+	}
+
+	a := &AccountCreateRequestAttributes{Country: "GB", BankID: "089999", BankIDCode: "GBDSC", Bic: "NWBKGB22", AccountNumber: "66374959"}
+	if err := a.Validate(); err != nil {
+		t.Errorf("Validate without a modulus checker returned error: %v", err)
+	}
+
+	RegisterUKModulusChecker(iban.NewModulusChecker(weights))
+	defer RegisterUKModulusChecker(nil)
+
+	errs, _ := a.Validate().(FieldErrors)
+	if len(errs) != 1 || errs[0].Field != "account_number" {
+		t.Errorf("Validate with failing modulus check returned %v", errs)
+	}
+	a.AccountNumber = "66374958"
+	if err := a.Validate(); err != nil {
+		t.Errorf("Validate returned error: %v", err)
+	}
+}
+
+func TestCountryRules_IBANGeneration(t *testing.T) {
+	countryRules.RLock()
+	defer countryRules.RUnlock()
+	for country, r := range countryRules.m {
+		if r.IBANGeneration != iban.CanGenerate(country) {
+			t.Errorf("%v rules have IBANGeneration %v, iban.CanGenerate returns %v", country, r.IBANGeneration, iban.CanGenerate(country))
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package form3test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vslovik/form3/iban"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 100
+)
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	bicPattern      = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+)
+
+// account is a stored account. Attributes are kept as decoded JSON, so
+// that every attribute sent by a client is returned unchanged.
+type account struct {
+	Attributes     map[string]interface{} `json:"attributes"`
+	CreatedOn      time.Time              `json:"created_on"`
+	ID             string                 `json:"id"`
+	ModifiedOn     time.Time              `json:"modified_on"`
+	OrganisationID string                 `json:"organisation_id"`
+	Type           string                 `json:"type"`
+	Version        int                    `json:"version"`
+}
+
+type accountRequest struct {
+	Data *struct {
+		Attributes     map[string]interface{} `json:"attributes"`
+		ID             string                 `json:"id"`
+		OrganisationID string                 `json:"organisation_id"`
+		Type           string                 `json:"type"`
+		Version        *int                   `json:"version"`
+	} `json:"data"`
+}
+
+func selfLink(id string) map[string]string {
+	return map[string]string{"self": accountsPath + "/" + id}
+}
+
+func (h *Handler) createAccount(w http.ResponseWriter, r *http.Request) {
+	var req accountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Data == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	d := req.Data
+
+	var failures []string
+	if !uuidPattern.MatchString(d.ID) {
+		failures = append(failures, fmt.Sprintf("id in body must be of type uuid: %q", d.ID))
+	}
+	if !uuidPattern.MatchString(d.OrganisationID) {
+		failures = append(failures, fmt.Sprintf("organisation_id in body must be of type uuid: %q", d.OrganisationID))
+	}
+	if d.Type != "accounts" {
+		failures = append(failures, fmt.Sprintf("type in body should be one of [accounts]: %q", d.Type))
+	}
+	failures = append(failures, validateAttributes(d.Attributes, true)...)
+	if len(failures) > 0 {
+		writeValidationError(w, failures)
+		return
+	}
+
+	if _, ok := h.accounts[d.ID]; ok {
+		writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		return
+	}
+
+	now := h.Now().UTC()
+	a := &account{
+		Attributes:     d.Attributes,
+		CreatedOn:      now,
+		ID:             d.ID,
+		ModifiedOn:     now,
+		OrganisationID: d.OrganisationID,
+		Type:           "accounts",
+	}
+	h.accounts[a.ID] = a
+	h.order = append(h.order, a.ID)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"data": a, "links": selfLink(a.ID)})
+}
+
+func (h *Handler) fetchAccount(w http.ResponseWriter, r *http.Request, id string) {
+	if !uuidPattern.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+	a, ok := h.accounts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %v does not exist", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": a, "links": selfLink(id)})
+}
+
+func (h *Handler) updateAccount(w http.ResponseWriter, r *http.Request, id string) {
+	if !uuidPattern.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+	var req accountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Data == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	d := req.Data
+	if d.ID != id {
+		writeError(w, http.StatusBadRequest, "id in body does not match id in path")
+		return
+	}
+	if d.Version == nil {
+		writeError(w, http.StatusBadRequest, "version is required")
+		return
+	}
+	if failures := validateAttributes(d.Attributes, false); len(failures) > 0 {
+		writeValidationError(w, failures)
+		return
+	}
+
+	a, ok := h.accounts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %v does not exist", id))
+		return
+	}
+	if *d.Version != a.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	attributes := make(map[string]interface{}, len(a.Attributes))
+	for k, v := range a.Attributes {
+		attributes[k] = v
+	}
+	for k, v := range d.Attributes {
+		attributes[k] = v
+	}
+	a.Attributes = attributes
+	a.Version++
+	a.ModifiedOn = h.Now().UTC()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": a, "links": selfLink(id)})
+}
+
+func (h *Handler) deleteAccount(w http.ResponseWriter, r *http.Request, id string) {
+	if !uuidPattern.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+	a, ok := h.accounts[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if version != a.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	delete(h.accounts, id)
+	for i, v := range h.order {
+		if v == id {
+			h.order = append(h.order[:i], h.order[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) listAccounts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	size := defaultPageSize
+	if v := q.Get("page[size]"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid page size")
+			return
+		}
+		if n > maxPageSize {
+			n = maxPageSize
+		}
+		size = n
+	}
+
+	var ids []string
+	for _, id := range h.order {
+		if matchesFilters(h.accounts[id], q) {
+			ids = append(ids, id)
+		}
+	}
+	last := 0
+	if len(ids) > 0 {
+		last = (len(ids) - 1) / size
+	}
+
+	page := 0
+	switch v := q.Get("page[number]"); v {
+	case "", "first":
+	case "last":
+		page = last
+	default:
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid page number")
+			return
+		}
+		page = n
+	}
+
+	data := []*account{}
+	for i := page * size; i < len(ids) && i < (page+1)*size; i++ {
+		data = append(data, h.accounts[ids[i]])
+	}
+
+	link := func(page int) string {
+		v := url.Values{}
+		for k, vs := range q {
+			if strings.HasPrefix(k, "filter[") {
+				v[k] = vs
+			}
+		}
+		v.Set("page[number]", strconv.Itoa(page))
+		v.Set("page[size]", strconv.Itoa(size))
+		return accountsPath + "?" + v.Encode()
+	}
+	links := map[string]string{
+		"first": link(0),
+		"last":  link(last),
+		"self":  link(page),
+	}
+	if page < last {
+		links["next"] = link(page + 1)
+	}
+	if page > 0 && page <= last {
+		links["prev"] = link(page - 1)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data, "links": links})
+}
+
+// matchesFilters reports whether a matches the filter[...] parameters of q.
+// Comma separated values of a filter are alternatives.
+func matchesFilters(a *account, q url.Values) bool {
+	for k := range q {
+		if !strings.HasPrefix(k, "filter[") || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		name := k[len("filter[") : len(k)-1]
+		got, _ := a.Attributes[name].(string)
+		match := false
+		for _, want := range strings.Split(q.Get(k), ",") {
+			if got == want {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// validateAttributes returns the validation failures of the attributes of
+// a create request, or of a patch if create is false.
+func validateAttributes(attrs map[string]interface{}, create bool) []string {
+	if attrs == nil {
+		if create {
+			return []string{"attributes in body is required"}
+		}
+		return nil
+	}
+
+	var failures []string
+	str := func(name string, pattern *regexp.Regexp, required bool) {
+		v, present := attrs[name]
+		if !present {
+			if required {
+				failures = append(failures, fmt.Sprintf("%v in body is required", name))
+			}
+			return
+		}
+		s, ok := v.(string)
+		if !ok || !pattern.MatchString(s) {
+			failures = append(failures, fmt.Sprintf("%v in body should match '%v'", name, pattern))
+		}
+	}
+	str("country", countryPattern, create)
+	str("base_currency", currencyPattern, false)
+	str("bic", bicPattern, false)
+
+	if v, ok := attrs["iban"]; ok {
+		if s, _ := v.(string); iban.Validate(s) != nil {
+			failures = append(failures, "iban in body is not a valid IBAN")
+		}
+	}
+	if v, ok := attrs["account_classification"]; ok {
+		if v != "Personal" && v != "Business" {
+			failures = append(failures, "account_classification in body should be one of [Personal Business]")
+		}
+	}
+	if v, ok := attrs["name"]; ok {
+		if names, _ := v.([]interface{}); len(names) > 4 {
+			failures = append(failures, "name in body should have at most 4 items")
+		}
+	}
+	return failures
+}
+
+func writeValidationError(w http.ResponseWriter, failures []string) {
+	writeError(w, http.StatusBadRequest, "validation failure list:\n"+strings.Join(failures, "\n"))
+}
@@ -0,0 +1,429 @@
+package form3test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/vslovik/form3/iban"
+)
+
+// Mode tells whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeRecord sends requests with the underlying transport and records
+	// them with their responses.
+	ModeRecord Mode = iota
+	// ModeReplay answers requests with the recorded responses, without
+	// sending them.
+	ModeReplay
+)
+
+// Matching selects how replayed requests are matched with recorded ones.
+type Matching int
+
+const (
+	// MatchStrict requires the method, path, query and JSON body of a
+	// request to equal the recorded ones, and replays every interaction at
+	// most once, the earliest recorded first. JSON bodies are compared as
+	// values, so the order of the object keys does not matter.
+	MatchStrict Matching = iota
+	// MatchLenient requires the method and path of a request to equal the
+	// recorded ones, and the query and JSON body to contain the recorded
+	// ones: parameters and fields absent from the recording are ignored.
+	// Interactions may be replayed as many times as needed.
+	MatchLenient
+)
+
+// Interaction is a recorded request and response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request, after redaction.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"` // path and query
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response, after redaction.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records interactions with the API
+// into a golden file, or replays them from it. Sensitive data is redacted
+// before it is recorded: the values of the headers in RedactHeaders, the
+// values of the JSON fields in RedactFields, the values of the query
+// parameters in RedactParams or named after a field in RedactFields, e.g.
+// filter[password], and IBANs, of which only the country code and the last
+// four characters are kept. Replayed requests are redacted the same way
+// before they are matched.
+//
+// Plug it into the client with:
+//
+//	rec, err := form3test.NewRecorder("testdata/accounts.json", form3test.ModeReplay)
+//	client, _ := form3.NewClient(form3.WithHTTPClient(rec.Client()))
+//	...
+//	err = rec.Close() // writes the golden file in ModeRecord
+type Recorder struct {
+	// Transport sends the requests in ModeRecord. It defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	Matching Matching
+
+	RedactHeaders []string
+	RedactFields  []string
+	RedactParams  []string
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// DefaultRedactHeaders, DefaultRedactFields and DefaultRedactParams are
+// redacted by new recorders.
+var (
+	DefaultRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Signature", "X-Api-Key"}
+	DefaultRedactFields  = []string{"access_token", "refresh_token", "client_secret", "password"}
+	DefaultRedactParams  = []string{"filter[account_number]"}
+)
+
+const redacted = "REDACTED"
+
+var ibanCandidate = regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}\b`)
+
+// NewRecorder returns a recorder of the golden file at path. In ModeReplay
+// the file is loaded at once.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		path:          path,
+		mode:          mode,
+		RedactHeaders: DefaultRedactHeaders,
+		RedactFields:  DefaultRedactFields,
+		RedactParams:  DefaultRedactParams,
+	}
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("form3test: invalid golden file %v: %v", path, err)
+		}
+		r.used = make([]bool, len(r.interactions))
+	}
+	return r, nil
+}
+
+// Client returns an http.Client using the recorder as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded interactions.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Interaction(nil), r.interactions...)
+}
+
+// Close writes the golden file in ModeRecord. It does nothing in
+// ModeReplay.
+func (r *Recorder) Close() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// RoundTrip implements http.RoundTripper. In ModeReplay it fails if no
+// recorded interaction matches req.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    r.redactURL(req.URL),
+		Header: r.redactHeader(req.Header),
+		Body:   r.redactBody(body),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, &recorded)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, &Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.redactHeader(resp.Header),
+			Body:       r.redactBody(respBody),
+		},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, in := range r.interactions {
+		if r.Matching == MatchStrict && r.used[i] || !r.matches(&in.Request, recorded) {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("form3test: no recorded interaction matches %v %v", recorded.Method, recorded.URL)
+	}
+	r.used[match] = true
+
+	rec := r.interactions[match].Response
+	header := make(http.Header, len(rec.Header))
+	for k, v := range rec.Header {
+		header[k] = append([]string(nil), v...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		StatusCode:    rec.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// matches reports whether the request got matches the recorded request
+// want.
+func (r *Recorder) matches(want, got *RecordedRequest) bool {
+	if want.Method != got.Method {
+		return false
+	}
+	wu, err1 := url.Parse(want.URL)
+	gu, err2 := url.Parse(got.URL)
+	if err1 != nil || err2 != nil || wu.Path != gu.Path {
+		return false
+	}
+	if r.Matching == MatchStrict {
+		return reflect.DeepEqual(wu.Query(), gu.Query()) && matchJSON(want.Body, got.Body, reflect.DeepEqual)
+	}
+	gq := gu.Query()
+	for k, v := range wu.Query() {
+		if !reflect.DeepEqual(v, gq[k]) {
+			return false
+		}
+	}
+	return matchJSON(want.Body, got.Body, containsJSON)
+}
+
+// matchJSON compares the JSON bodies a and b with match, or as strings if
+// they are not both JSON.
+func matchJSON(a, b string, match func(a, b interface{}) bool) bool {
+	if a == b {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return match(va, vb)
+}
+
+// containsJSON reports whether the decoded JSON value got contains want:
+// objects may have more fields than in want, other values must be equal.
+func containsJSON(want, got interface{}) bool {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range w {
+			if gv, ok := g[k]; !ok || !containsJSON(v, gv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !containsJSON(w[i], g[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+func (r *Recorder) redactHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		out[k] = append([]string(nil), v...)
+	}
+	for _, k := range r.RedactHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(k)]; ok {
+			out.Set(k, redacted)
+		}
+	}
+	return out
+}
+
+// redactURL returns the path and query of u with the sensitive query
+// parameters and the IBANs of the others redacted.
+func (r *Recorder) redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+	q := u.Query()
+	for k, v := range q {
+		for i := range v {
+			if r.sensitiveParam(k) {
+				v[i] = redacted
+			} else {
+				v[i] = ibanCandidate.ReplaceAllStringFunc(v[i], maskIBAN)
+			}
+		}
+	}
+	return u.EscapedPath() + "?" + q.Encode()
+}
+
+// redactBody redacts the sensitive fields and the IBANs of a JSON body, or
+// the IBANs only of another body.
+func (r *Recorder) redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return ibanCandidate.ReplaceAllStringFunc(string(body), maskIBAN)
+	}
+	data, err := json.Marshal(r.redactValue(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+func (r *Recorder) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if r.sensitive(k) {
+				v[k] = redacted
+			} else {
+				v[k] = r.redactValue(e)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = r.redactValue(e)
+		}
+		return v
+	case string:
+		// Links, e.g. of pagination, repeat the query of the request.
+		if strings.HasPrefix(v, "/") && strings.Contains(v, "?") {
+			if u, err := url.Parse(v); err == nil {
+				return r.redactURL(u)
+			}
+		}
+		return ibanCandidate.ReplaceAllStringFunc(v, maskIBAN)
+	}
+	return v
+}
+
+func (r *Recorder) sensitive(field string) bool {
+	for _, f := range r.RedactFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveParam reports whether the query parameter param is in
+// RedactParams, or is named after a field in RedactFields, either directly
+// or in brackets as in filter[field].
+func (r *Recorder) sensitiveParam(param string) bool {
+	for _, p := range r.RedactParams {
+		if p == param {
+			return true
+		}
+	}
+	if i := strings.LastIndexByte(param, '['); i >= 0 && strings.HasSuffix(param, "]") {
+		return r.sensitive(param[i+1 : len(param)-1])
+	}
+	return r.sensitive(param)
+}
+
+// maskIBAN keeps the country code and the last four characters of a valid
+// IBAN and masks the others. Other strings are returned unchanged.
+func maskIBAN(s string) string {
+	if iban.Validate(s) != nil {
+		return s
+	}
+	return s[:2] + strings.Repeat("*", len(s)-6) + s[len(s)-4:]
+}
@@ -0,0 +1,199 @@
+package form3test_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vslovik/form3"
+	"github.com/vslovik/form3/form3test"
+)
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "form3test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// record creates and fetches an account through a recorder against the fake
+// server, and returns the path of the golden file written in dir.
+func record(t *testing.T, dir string) string {
+	srv := form3test.NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(dir, "accounts.json")
+	rec, err := form3test.NewRecorder(path, form3test.ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	client, _ := form3.NewClient(
+		form3.WithBaseURL(srv.URL),
+		form3.WithHTTPClient(rec.Client()),
+		form3.WithHeader("Authorization", "Bearer secret-token"),
+	)
+	ctx := context.Background()
+	if _, _, _, err := client.Account.Create(ctx, accountID, organisationID, newAttributes()); err != nil {
+		t.Fatalf("Account.Create returned error: %v", err)
+	}
+	if _, _, _, err := client.Account.Fetch(ctx, accountID); err != nil {
+		t.Fatalf("Account.Fetch returned error: %v", err)
+	}
+	if n := len(rec.Interactions()); n != 2 {
+		t.Errorf("Recorder recorded %d interactions, want 2", n)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	return path
+}
+
+func replayClient(t *testing.T, path string, matching form3test.Matching) *form3.Client {
+	rec, err := form3test.NewRecorder(path, form3test.ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	rec.Matching = matching
+	client, _ := form3.NewClient(
+		form3.WithBaseURL("http://form3.invalid/"),
+		form3.WithHTTPClient(rec.Client()),
+		form3.WithRetryPolicy(form3.RetryPolicy{MaxAttempts: 1}),
+	)
+	return client
+}
+
+func TestRecorder_Redaction(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	data, err := ioutil.ReadFile(record(t, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := string(data)
+	for _, secret := range []string{"secret-token", testIBAN} {
+		if strings.Contains(golden, secret) {
+			t.Errorf("Golden file contains %q", secret)
+		}
+	}
+	for _, want := range []string{"REDACTED", "GB****************0004"} {
+		if !strings.Contains(golden, want) {
+			t.Errorf("Golden file does not contain %q", want)
+		}
+	}
+}
+
+func TestRecorder_RedactionOfFilters(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	srv := form3test.NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(dir, "list.json")
+	rec, err := form3test.NewRecorder(path, form3test.ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	client, _ := form3.NewClient(form3.WithBaseURL(srv.URL), form3.WithHTTPClient(rec.Client()))
+	opts := &form3.AccountListOptions{AccountListFilter: form3.AccountListFilter{
+		AccountNumber: []string{"41426819"},
+		Iban:          []string{testIBAN},
+		Country:       []string{"GB"},
+	}}
+	if _, _, _, err := client.Account.List(context.Background(), opts); err != nil {
+		t.Fatalf("Account.List returned error: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := string(data)
+	for _, secret := range []string{"41426819", testIBAN} {
+		if strings.Contains(golden, secret) {
+			t.Errorf("Golden file contains %q", secret)
+		}
+	}
+	for _, want := range []string{"filter%5Baccount_number%5D=REDACTED", "GB%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A0004", "filter%5Bcountry%5D=GB"} {
+		if !strings.Contains(golden, want) {
+			t.Errorf("Golden file does not contain %q", want)
+		}
+	}
+
+	// The filters of the replayed request are redacted the same way, so
+	// that it matches the recording.
+	client = replayClient(t, path, form3test.MatchStrict)
+	if _, _, _, err := client.Account.List(context.Background(), opts); err != nil {
+		t.Errorf("Account.List replay returned error: %v", err)
+	}
+}
+
+func TestRecorder_ReplayStrict(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	client := replayClient(t, record(t, dir), form3test.MatchStrict)
+	ctx := context.Background()
+
+	a := newAttributes()
+	a.CustomerID = "other"
+	if _, _, _, err := client.Account.Create(ctx, accountID, organisationID, a); err == nil {
+		t.Errorf("Account.Create with another body returned no error")
+	}
+
+	created, _, _, err := client.Account.Create(ctx, accountID, organisationID, newAttributes())
+	if err != nil {
+		t.Fatalf("Account.Create returned error: %v", err)
+	}
+	if created.ID != accountID || created.Attributes.Iban != "GB****************0004" {
+		t.Errorf("Account.Create returned %+v", created)
+	}
+	if _, _, _, err := client.Account.Fetch(ctx, accountID); err != nil {
+		t.Errorf("Account.Fetch returned error: %v", err)
+	}
+	if _, _, _, err := client.Account.Fetch(ctx, accountID); err == nil {
+		t.Errorf("Account.Fetch replayed twice returned no error")
+	}
+}
+
+func TestRecorder_ReplayLenient(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	client := replayClient(t, record(t, dir), form3test.MatchLenient)
+	ctx := context.Background()
+
+	a := newAttributes()
+	a.CustomerID = "other"
+	if _, _, _, err := client.Account.Create(ctx, accountID, organisationID, a); err != nil {
+		t.Errorf("Account.Create with an extra field returned error: %v", err)
+	}
+	a = newAttributes()
+	a.Bic = "BARCGB22"
+	if _, _, _, err := client.Account.Create(ctx, accountID, organisationID, a); err == nil {
+		t.Errorf("Account.Create with another BIC returned no error")
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, _, err := client.Account.Fetch(ctx, accountID); err != nil {
+			t.Errorf("Account.Fetch returned error: %v", err)
+		}
+	}
+}
+
+func TestNewRecorder_MissingGoldenFile(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	_, err := form3test.NewRecorder(filepath.Join(dir, "missing.json"), form3test.ModeReplay)
+	if !os.IsNotExist(err) {
+		t.Errorf("NewRecorder returned %v, want a not exist error", err)
+	}
+}
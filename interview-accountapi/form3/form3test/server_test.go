@@ -0,0 +1,207 @@
+package form3test_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vslovik/form3"
+	"github.com/vslovik/form3/form3test"
+	"github.com/vslovik/form3/iban"
+)
+
+const (
+	accountID      = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	organisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+)
+
+// testIBAN is the IBAN of the test accounts, with their bank ID and account
+// number.
+var testIBAN = generateIBAN("GB", "NWBK400300", "10000004")
+
+func generateIBAN(country, bankID, accountNumber string) string {
+	s, err := iban.Generate(country, bankID, accountNumber)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func newAttributes() *form3.AccountCreateRequestAttributes {
+	return &form3.AccountCreateRequestAttributes{
+		BankID:       "400300",
+		BankIDCode:   "GBDSC",
+		BaseCurrency: "GBP",
+		Bic:          "NWBKGB22",
+		Country:      "GB",
+		Iban:         testIBAN,
+		Name:         []string{"Samantha Holder"},
+	}
+}
+
+func setup(t *testing.T) (*form3.Client, *form3test.Server) {
+	srv := form3test.NewServer()
+	srv.Now = func() time.Time { return time.Date(2020, time.November, 11, 10, 40, 44, 0, time.UTC) }
+	client, err := form3.NewClient(form3.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	return client, srv
+}
+
+func TestServer_CreateFetchUpdateDelete(t *testing.T) {
+	client, srv := setup(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	created, links, _, err := client.Account.Create(ctx, accountID, organisationID, newAttributes())
+	if err != nil {
+		t.Fatalf("Account.Create returned error: %v", err)
+	}
+	if created.Version != 0 || created.Attributes.Name[0] != "Samantha Holder" {
+		t.Errorf("Account.Create returned %+v", created)
+	}
+	if links.Self != "/v1/organisation/accounts/"+accountID {
+		t.Errorf("Account.Create returned links %+v", links)
+	}
+
+	if _, _, _, err := client.Account.Create(ctx, accountID, organisationID, newAttributes()); !form3.IsConflict(err) {
+		t.Errorf("Account.Create of a duplicate returned %v, want a conflict", err)
+	}
+
+	fetched, _, _, err := client.Account.Fetch(ctx, accountID)
+	if err != nil {
+		t.Fatalf("Account.Fetch returned error: %v", err)
+	}
+	if fetched.OrganisationID != organisationID || fetched.Attributes.Iban != testIBAN {
+		t.Errorf("Account.Fetch returned %+v", fetched)
+	}
+
+	patch := &form3.AccountUpdateRequestAttributes{CustomerID: form3.String("567")}
+	if _, _, _, err := client.Account.Update(ctx, accountID, 1, patch); !form3.IsConflict(err) {
+		t.Errorf("Account.Update with a stale version returned %v, want a conflict", err)
+	}
+	updated, _, _, err := client.Account.Update(ctx, accountID, 0, patch)
+	if err != nil {
+		t.Fatalf("Account.Update returned error: %v", err)
+	}
+	if updated.Version != 1 || updated.Attributes.CustomerID != "567" || updated.Attributes.Bic != "NWBKGB22" {
+		t.Errorf("Account.Update returned %+v", updated)
+	}
+
+	if _, err := client.Account.Delete(ctx, accountID, 0); !form3.IsConflict(err) {
+		t.Errorf("Account.Delete with a stale version returned %v, want a conflict", err)
+	}
+	resp, err := client.Account.Delete(ctx, accountID, 1)
+	if err != nil {
+		t.Fatalf("Account.Delete returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Account.Delete returned status %v, want 204", resp.StatusCode)
+	}
+	if _, _, _, err := client.Account.Fetch(ctx, accountID); !form3.IsNotFound(err) {
+		t.Errorf("Account.Fetch of a deleted account returned %v, want not found", err)
+	}
+	if srv.Len() != 0 {
+		t.Errorf("Server holds %d accounts, want 0", srv.Len())
+	}
+}
+
+func TestServer_Validation(t *testing.T) {
+	client, srv := setup(t)
+	defer srv.Close()
+
+	tests := []struct {
+		id, organisationID string
+		modify             func(a *form3.AccountCreateRequestAttributes)
+		message            string
+	}{
+		{"x", organisationID, func(a *form3.AccountCreateRequestAttributes) {}, "id in body must be of type uuid"},
+		{accountID, "", func(a *form3.AccountCreateRequestAttributes) {}, "organisation_id in body must be of type uuid"},
+		{accountID, organisationID, func(a *form3.AccountCreateRequestAttributes) { a.Country = "x" }, "country in body should match"},
+		{accountID, organisationID, func(a *form3.AccountCreateRequestAttributes) { a.BaseCurrency = "x" }, "base_currency in body should match"},
+		{accountID, organisationID, func(a *form3.AccountCreateRequestAttributes) { a.Bic = "x" }, "bic in body should match"},
+		{accountID, organisationID, func(a *form3.AccountCreateRequestAttributes) { a.Iban = "x" }, "iban in body is not a valid IBAN"},
+		{accountID, organisationID, func(a *form3.AccountCreateRequestAttributes) { a.AccountClassification = "x" }, "account_classification in body should be one of"},
+	}
+	for _, tt := range tests {
+		a := newAttributes()
+		tt.modify(a)
+		_, _, _, err := client.Account.Create(context.Background(), tt.id, tt.organisationID, a)
+		if !form3.IsValidation(err) {
+			t.Errorf("Account.Create returned %v, want a validation error", err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("Account.Create returned %q, want it to contain %q", err, tt.message)
+		}
+	}
+
+	if _, _, _, err := client.Account.Fetch(context.Background(), "x"); !form3.IsValidation(err) {
+		t.Errorf("Account.Fetch of an invalid id returned %v, want a validation error", err)
+	}
+	if srv.Len() != 0 {
+		t.Errorf("Server holds %d accounts, want 0", srv.Len())
+	}
+}
+
+func TestServer_List(t *testing.T) {
+	client, srv := setup(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	ids := []string{
+		"00000000-0000-4000-8000-000000000001",
+		"00000000-0000-4000-8000-000000000002",
+		"00000000-0000-4000-8000-000000000003",
+	}
+	for i, id := range ids {
+		a := newAttributes()
+		a.CustomerID = []string{"a", "b", "a"}[i]
+		if _, _, _, err := client.Account.Create(ctx, id, organisationID, a); err != nil {
+			t.Fatalf("Account.Create returned error: %v", err)
+		}
+	}
+
+	opts := &form3.AccountListOptions{ListOptions: form3.ListOptions{PerPage: 2}}
+	accounts, links, _, err := client.Account.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("Account.List returned error: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].ID != ids[0] {
+		t.Errorf("Account.List returned %d accounts", len(accounts))
+	}
+	if links.Next == "" || links.Prev != "" || links.Last == links.First {
+		t.Errorf("Account.List returned links %+v", links)
+	}
+
+	var got []string
+	it := client.Account.ListAll(ctx, opts)
+	for it.Next() {
+		got = append(got, it.Account().ID)
+	}
+	if it.Err() != nil || len(got) != 3 {
+		t.Errorf("Account.ListAll returned %v, error %v", got, it.Err())
+	}
+
+	opts.Page = 5
+	if accounts, _, _, _ := client.Account.List(ctx, opts); len(accounts) != 0 {
+		t.Errorf("Account.List past the last page returned %d accounts", len(accounts))
+	}
+
+	filtered := &form3.AccountListOptions{AccountListFilter: form3.AccountListFilter{CustomerID: []string{"a"}}}
+	accounts, _, _, err = client.Account.List(ctx, filtered)
+	if err != nil {
+		t.Fatalf("Account.List returned error: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].ID != ids[0] || accounts[1].ID != ids[2] {
+		t.Errorf("Account.List with filter returned %d accounts", len(accounts))
+	}
+
+	srv.Reset()
+	if accounts, _, _, _ := client.Account.List(ctx, nil); len(accounts) != 0 {
+		t.Errorf("Account.List after Reset returned %d accounts", len(accounts))
+	}
+}
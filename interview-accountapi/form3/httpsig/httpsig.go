@@ -0,0 +1,139 @@
+// Package httpsig signs and verifies HTTP requests as required by the Form3
+// API, following the HTTP Signatures draft
+// (https://tools.ietf.org/html/draft-cavage-http-signatures-10) with the
+// rsa-sha256 algorithm:
+//
+//	Authorization: Signature keyId="...",algorithm="rsa-sha256",headers="(request-target) host date digest",signature="..."
+//
+// The Digest header holds the SHA-256 digest of the request body, so that
+// the signature covers the body too.
+package httpsig
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Algorithm is the only signature algorithm supported.
+const Algorithm = "rsa-sha256"
+
+// DefaultHeaders are the headers signed by a Signer and required by a
+// Verifier, unless configured otherwise.
+var DefaultHeaders = []string{"(request-target)", "host", "date", "digest"}
+
+var (
+	ErrMissingSignature = errors.New("httpsig: missing signature")
+	ErrMalformed        = errors.New("httpsig: malformed signature")
+	ErrUnknownKey       = errors.New("httpsig: unknown key")
+	ErrInvalidSignature = errors.New("httpsig: invalid signature")
+	ErrDigestMismatch   = errors.New("httpsig: digest does not match the body")
+	ErrDateSkew         = errors.New("httpsig: date is too far from the current time")
+)
+
+// ParsePrivateKey parses an RSA private key in PEM format, in either PKCS #1
+// ("RSA PRIVATE KEY") or PKCS #8 ("PRIVATE KEY") form.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("httpsig: no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("httpsig: invalid private key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("httpsig: private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// ParsePublicKey parses an RSA public key in PEM format, in either PKIX
+// ("PUBLIC KEY") or PKCS #1 ("RSA PUBLIC KEY") form.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("httpsig: no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("httpsig: invalid public key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("httpsig: public key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Digest returns the value of the Digest header for body.
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// readBody returns the body of req, leaving req able to send it.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+// signingString returns the string signed for the given headers of req.
+func signingString(req *http.Request, headers []string) (string, error) {
+	lines := make([]string, len(headers))
+	for i, h := range headers {
+		var v string
+		switch h {
+		case "(request-target)":
+			v = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			v = req.Host
+			if v == "" {
+				v = req.URL.Host
+			}
+		default:
+			values := req.Header[http.CanonicalHeaderKey(h)]
+			if len(values) == 0 {
+				return "", fmt.Errorf("%w: header %v is not set", ErrMalformed, h)
+			}
+			v = strings.Join(values, ", ")
+		}
+		lines[i] = h + ": " + v
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,87 @@
+package httpsig
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"sync"
+	"testing"
+)
+
+var (
+	keyOnce sync.Once
+	testKey *rsa.PrivateKey
+)
+
+// key returns an RSA key shared by the tests, as generating one is slow.
+func key(t *testing.T) *rsa.PrivateKey {
+	keyOnce.Do(func() {
+		var err error
+		if testKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			t.Fatalf("GenerateKey returned error: %v", err)
+		}
+	})
+	return testKey
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	k := key(t)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(k)
+	for _, block := range []*pem.Block{
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)},
+		{Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		got, err := ParsePrivateKey(pem.EncodeToMemory(block))
+		if err != nil {
+			t.Errorf("ParsePrivateKey(%v) returned error: %v", block.Type, err)
+			continue
+		}
+		if got.N.Cmp(k.N) != 0 || got.D.Cmp(k.D) != 0 {
+			t.Errorf("ParsePrivateKey(%v) returned another key", block.Type)
+		}
+	}
+	if _, err := ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Errorf("ParsePrivateKey of garbage returned no error")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	k := key(t)
+	pkix, _ := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	for _, block := range []*pem.Block{
+		{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&k.PublicKey)},
+		{Type: "PUBLIC KEY", Bytes: pkix},
+	} {
+		got, err := ParsePublicKey(pem.EncodeToMemory(block))
+		if err != nil {
+			t.Errorf("ParsePublicKey(%v) returned error: %v", block.Type, err)
+			continue
+		}
+		if got.N.Cmp(k.N) != 0 || got.E != k.E {
+			t.Errorf("ParsePublicKey(%v) returned another key", block.Type)
+		}
+	}
+}
+
+func TestDigest(t *testing.T) {
+	// SHA-256 of the empty string.
+	if got, want := Digest(nil), "SHA-256=47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="; got != want {
+		t.Errorf("Digest(nil) = %v, want %v", got, want)
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	got, err := parseParams(`keyId="k",algorithm="rsa-sha256", headers="(request-target) host",signature="YQ=="`)
+	if err != nil {
+		t.Fatalf("parseParams returned error: %v", err)
+	}
+	if got["keyId"] != "k" || got["headers"] != "(request-target) host" || got["signature"] != "YQ==" {
+		t.Errorf("parseParams returned %v", got)
+	}
+	for _, s := range []string{`keyId`, `keyId=k`, `keyId="k`} {
+		if _, err := parseParams(s); err == nil {
+			t.Errorf("parseParams(%q) returned no error", s)
+		}
+	}
+}
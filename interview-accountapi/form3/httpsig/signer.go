@@ -0,0 +1,101 @@
+package httpsig
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Signer signs requests with an RSA private key.
+type Signer struct {
+	KeyID string
+	Key   *rsa.PrivateKey
+
+	// Headers signed, DefaultHeaders if empty.
+	Headers []string
+
+	// Now returns the time set in the Date header. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewSigner returns a signer using the RSA private key in PEM format
+// identified by keyID at the API.
+func NewSigner(keyID string, privateKeyPEM []byte) (*Signer, error) {
+	if keyID == "" {
+		return nil, errors.New("httpsig: key ID is required")
+	}
+	key, err := ParsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{KeyID: keyID, Key: key}, nil
+}
+
+// Sign sets the Date header of req to the current time, its Digest header
+// to the digest of its body and its Authorization header to the signature
+// of req. Signing a request again, e.g. before retrying it, replaces them.
+func (s *Signer) Sign(req *http.Request) error {
+	headers := s.Headers
+	if len(headers) == 0 {
+		headers = DefaultHeaders
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	req.Header.Set("Date", now().UTC().Format(http.TimeFormat))
+
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Digest", Digest(body))
+
+	str, err := signingString(req, headers)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256([]byte(str))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.Key, crypto.SHA256, hash[:])
+	if err != nil {
+		return fmt.Errorf("httpsig: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(`Signature keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		s.KeyID, Algorithm, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(sig)))
+	return nil
+}
+
+// Transport is an http.RoundTripper signing every request with Signer
+// before sending it with Base.
+type Transport struct {
+	Signer *Signer
+
+	// Base sends the signed requests. It defaults to http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper. req is not modified, a signed copy
+// is sent instead.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed := req.Clone(req.Context())
+	if err := t.Signer.Sign(signed); err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(signed)
+}
@@ -0,0 +1,101 @@
+package httpsig
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2020, time.November, 11, 10, 40, 44, 0, time.UTC)
+
+func testSigner(t *testing.T) *Signer {
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key(t))})
+	s, err := NewSigner("key-1", pemKey)
+	if err != nil {
+		t.Fatalf("NewSigner returned error: %v", err)
+	}
+	s.Now = func() time.Time { return testNow }
+	return s
+}
+
+func testVerifier(t *testing.T) *Verifier {
+	v := NewVerifier(map[string]*rsa.PublicKey{"key-1": &key(t).PublicKey})
+	v.Now = func() time.Time { return testNow }
+	return v
+}
+
+func TestNewSigner_Errors(t *testing.T) {
+	if _, err := NewSigner("", nil); err == nil {
+		t.Errorf("NewSigner without key ID returned no error")
+	}
+	if _, err := NewSigner("key-1", []byte("x")); err == nil {
+		t.Errorf("NewSigner with an invalid key returned no error")
+	}
+}
+
+func TestSigner_Sign(t *testing.T) {
+	req, _ := http.NewRequest("POST", "http://api.form3.tech/v1/organisation/accounts?x=1", strings.NewReader(`{"data":{}}`))
+	if err := testSigner(t).Sign(req); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+
+	if got, want := req.Header.Get("Date"), "Wed, 11 Nov 2020 10:40:44 GMT"; got != want {
+		t.Errorf("Date header is %v, want %v", got, want)
+	}
+	if got, want := req.Header.Get("Digest"), Digest([]byte(`{"data":{}}`)); got != want {
+		t.Errorf("Digest header is %v, want %v", got, want)
+	}
+	auth := req.Header.Get("Authorization")
+	for _, want := range []string{
+		`Signature keyId="key-1"`,
+		`algorithm="rsa-sha256"`,
+		`headers="(request-target) host date digest"`,
+		`signature="`,
+	} {
+		if !strings.Contains(auth, want) {
+			t.Errorf("Authorization header %q does not contain %q", auth, want)
+		}
+	}
+
+	body, _ := ioutil.ReadAll(req.Body)
+	if string(body) != `{"data":{}}` {
+		t.Errorf("Request body after Sign is %q", body)
+	}
+	req.Body = ioutil.NopCloser(strings.NewReader(string(body)))
+	if err := testVerifier(t).Verify(req); err != nil {
+		t.Errorf("Verify returned error: %v", err)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	v := testVerifier(t)
+	srv := httptest.NewServer(v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{Signer: testSigner(t)}}
+	resp, err := client.Post(srv.URL+"/v1/organisation/accounts", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Signed request returned status %v, want 204", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/v1/organisation/accounts")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Unsigned request returned status %v, want 401", resp.StatusCode)
+	}
+}
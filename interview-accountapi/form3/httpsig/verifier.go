@@ -0,0 +1,155 @@
+package httpsig
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultMaxSkew is the default largest difference accepted between the
+// Date header of a request and the current time.
+const DefaultMaxSkew = 5 * time.Minute
+
+// Verifier verifies signed requests, e.g. in a fake server under test.
+type Verifier struct {
+	// Keys holds the public keys by key ID.
+	Keys map[string]*rsa.PublicKey
+
+	// Headers that must be signed, DefaultHeaders if empty.
+	Headers []string
+
+	// MaxSkew is the largest difference accepted between the Date header
+	// and the current time. Zero means DefaultMaxSkew, a negative value
+	// disables the check.
+	MaxSkew time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewVerifier returns a verifier accepting signatures made with the keys.
+func NewVerifier(keys map[string]*rsa.PublicKey) *Verifier {
+	return &Verifier{Keys: keys}
+}
+
+// Verify checks the signature of req, the digest of its body and its date.
+// The returned error wraps one of the Err... values of this package.
+func (v *Verifier) Verify(req *http.Request) error {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Signature ") {
+		return ErrMissingSignature
+	}
+	params, err := parseParams(strings.TrimPrefix(auth, "Signature "))
+	if err != nil {
+		return err
+	}
+
+	if alg := params["algorithm"]; alg != "" && alg != Algorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrMalformed, alg)
+	}
+	headers := strings.Fields(params["headers"])
+	if len(headers) == 0 {
+		headers = []string{"date"}
+	}
+	required := v.Headers
+	if len(required) == 0 {
+		required = DefaultHeaders
+	}
+	for _, h := range required {
+		if !contains(headers, h) {
+			return fmt.Errorf("%w: header %v is not signed", ErrMalformed, h)
+		}
+	}
+
+	key, ok := v.Keys[params["keyId"]]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownKey, params["keyId"])
+	}
+	sig, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("%w: signature is not base64", ErrMalformed)
+	}
+	str, err := signingString(req, headers)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256([]byte(str))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig); err != nil {
+		return ErrInvalidSignature
+	}
+
+	if contains(headers, "digest") {
+		body, err := readBody(req)
+		if err != nil {
+			return err
+		}
+		if req.Header.Get("Digest") != Digest(body) {
+			return ErrDigestMismatch
+		}
+	}
+
+	if v.MaxSkew >= 0 && contains(headers, "date") {
+		date, err := http.ParseTime(req.Header.Get("Date"))
+		if err != nil {
+			return fmt.Errorf("%w: invalid date", ErrMalformed)
+		}
+		now := time.Now
+		if v.Now != nil {
+			now = v.Now
+		}
+		maxSkew := v.MaxSkew
+		if maxSkew == 0 {
+			maxSkew = DefaultMaxSkew
+		}
+		if d := now().Sub(date); d > maxSkew || d < -maxSkew {
+			return ErrDateSkew
+		}
+	}
+	return nil
+}
+
+// Middleware returns a handler answering requests that fail verification
+// with 401 Unauthorized, and passing the others to next.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := v.Verify(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseParams parses the comma separated key="value" parameters of a
+// signature.
+func parseParams(s string) (map[string]string, error) {
+	params := make(map[string]string)
+	for s = strings.TrimSpace(s); s != ""; {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 || len(s) < eq+2 || s[eq+1] != '"' {
+			return nil, fmt.Errorf("%w: %q", ErrMalformed, s)
+		}
+		end := strings.IndexByte(s[eq+2:], '"')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated value in %q", ErrMalformed, s)
+		}
+		params[strings.TrimSpace(s[:eq])] = s[eq+2 : eq+2+end]
+		s = strings.TrimSpace(s[eq+2+end+1:])
+		s = strings.TrimSpace(strings.TrimPrefix(s, ","))
+	}
+	return params, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
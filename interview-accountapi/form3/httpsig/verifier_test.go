@@ -0,0 +1,64 @@
+package httpsig
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedRequest(t *testing.T) *http.Request {
+	req, _ := http.NewRequest("POST", "http://api.form3.tech/v1/organisation/accounts", strings.NewReader(`{"data":{}}`))
+	if err := testSigner(t).Sign(req); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	return req
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req *http.Request, v *Verifier)
+		want   error
+	}{
+		{"valid", func(req *http.Request, v *Verifier) {}, nil},
+		{"unsigned", func(req *http.Request, v *Verifier) { req.Header.Del("Authorization") }, ErrMissingSignature},
+		{"unknown key", func(req *http.Request, v *Verifier) { delete(v.Keys, "key-1") }, ErrUnknownKey},
+		{"other path", func(req *http.Request, v *Verifier) { req.URL.Path = "/v1/other" }, ErrInvalidSignature},
+		{"other date", func(req *http.Request, v *Verifier) {
+			req.Header.Set("Date", testNow.Add(time.Second).Format(http.TimeFormat))
+		}, ErrInvalidSignature},
+		{"other body", func(req *http.Request, v *Verifier) {
+			req.Body = ioutil.NopCloser(strings.NewReader(`{"data":{"id":"x"}}`))
+			req.GetBody = nil
+		}, ErrDigestMismatch},
+		{"stale", func(req *http.Request, v *Verifier) {
+			v.Now = func() time.Time { return testNow.Add(time.Hour) }
+		}, ErrDateSkew},
+		{"unsigned header", func(req *http.Request, v *Verifier) { v.Headers = []string{"content-type"} }, ErrMalformed},
+		{"algorithm", func(req *http.Request, v *Verifier) {
+			req.Header.Set("Authorization", strings.Replace(req.Header.Get("Authorization"), Algorithm, "hmac-sha256", 1))
+		}, ErrMalformed},
+	}
+	for _, tt := range tests {
+		req := signedRequest(t)
+		v := testVerifier(t)
+		tt.modify(req, v)
+		err := v.Verify(req)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("Verify of %v request returned %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestVerifier_SkewDisabled(t *testing.T) {
+	req := signedRequest(t)
+	v := testVerifier(t)
+	v.Now = func() time.Time { return testNow.Add(time.Hour) }
+	v.MaxSkew = -1
+	if err := v.Verify(req); err != nil {
+		t.Errorf("Verify returned error: %v", err)
+	}
+}
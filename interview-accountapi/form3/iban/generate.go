@@ -0,0 +1,172 @@
+package iban
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// bbanGenerator builds the BBAN of a country from a bank ID and an account
+// number, both given as used in that country.
+type bbanGenerator func(bankID, accountNumber string) (string, error)
+
+var generators = map[string]bbanGenerator{
+	// Bank ID is the four letter bank code of the BIC followed by the sort code.
+	"GB": concat(bankCodeAndSortCode, digits(8, false)),
+	"IE": concat(bankCodeAndSortCode, digits(8, false)),
+	"NL": concat(letters(4), digits(10, true)),
+	"DE": concat(digits(8, false), digits(10, true)),
+	"AT": concat(digits(5, false), digits(11, true)),
+	"CH": concat(digits(5, false), alnum(12)),
+	"LU": concat(digits(3, false), alnum(13)),
+	"GR": concat(digits(7, false), alnum(16)),
+	"PL": concat(digits(8, false), digits(16, false)),
+	"BE": belgianBBAN,
+	"ES": spanishBBAN,
+	"PT": portugueseBBAN,
+}
+
+// Generate builds the IBAN, in electronic format, of the account with the
+// given national bank ID and account number. For GB and IE the bank ID is
+// the four letter bank code of the BIC followed by the sort code, e.g.
+// "NWBK400300"; for NL it is the four letter bank code.
+func Generate(country, bankID, accountNumber string) (string, error) {
+	gen, ok := generators[country]
+	if !ok {
+		return "", fmt.Errorf("%w: cannot generate %v IBANs", ErrUnsupportedCountry, country)
+	}
+	bban, err := gen(strings.ToUpper(bankID), strings.ToUpper(accountNumber))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v %v", ErrFormat, country, err)
+	}
+	return country + CheckDigits(country, bban) + bban, nil
+}
+
+// CanGenerate reports whether Generate supports country.
+func CanGenerate(country string) bool {
+	_, ok := generators[country]
+	return ok
+}
+
+// GenerationCountries returns the countries supported by Generate, sorted.
+func GenerationCountries() []string {
+	var c []string
+	for country := range generators {
+		c = append(c, country)
+	}
+	sort.Strings(c)
+	return c
+}
+
+// part checks and normalizes one part of a BBAN.
+type part func(s string) (string, error)
+
+func concat(bank, account part) bbanGenerator {
+	return func(bankID, accountNumber string) (string, error) {
+		b, err := bank(bankID)
+		if err != nil {
+			return "", fmt.Errorf("bank ID %q: %v", bankID, err)
+		}
+		a, err := account(accountNumber)
+		if err != nil {
+			return "", fmt.Errorf("account number %q: %v", accountNumber, err)
+		}
+		return b + a, nil
+	}
+}
+
+// digits accepts n digits, or up to n digits left padded with zeros if pad
+// is set.
+func digits(n int, pad bool) part {
+	return func(s string) (string, error) {
+		if pad && len(s) < n {
+			s = strings.Repeat("0", n-len(s)) + s
+		}
+		if len(s) != n || !isDigits(s) {
+			return "", fmt.Errorf("must have %d digits", n)
+		}
+		return s, nil
+	}
+}
+
+// alnum accepts up to n letters or digits, left padded with zeros.
+func alnum(n int) part {
+	return func(s string) (string, error) {
+		if len(s) > n || !isAlnum(s) {
+			return "", fmt.Errorf("must have up to %d letters or digits", n)
+		}
+		return strings.Repeat("0", n-len(s)) + s, nil
+	}
+}
+
+func letters(n int) part {
+	return func(s string) (string, error) {
+		if len(s) != n || !isUpper(s) {
+			return "", fmt.Errorf("must have %d letters", n)
+		}
+		return s, nil
+	}
+}
+
+func bankCodeAndSortCode(s string) (string, error) {
+	if len(s) != 10 || !isUpper(s[:4]) || !isDigits(s[4:]) {
+		return "", fmt.Errorf("must be a four letter bank code followed by a 6 digit sort code")
+	}
+	return s, nil
+}
+
+// belgianBBAN appends the national check digits, the bank ID and account
+// number modulo 97, to a 3 digit bank ID and a 7 digit account number.
+func belgianBBAN(bankID, accountNumber string) (string, error) {
+	s, err := concat(digits(3, false), digits(7, true))(bankID, accountNumber)
+	if err != nil {
+		return "", err
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	check := n % 97
+	if check == 0 {
+		check = 97
+	}
+	return fmt.Sprintf("%s%02d", s, check), nil
+}
+
+// spanishBBAN inserts the two national check digits between an 8 digit bank
+// and branch code and a 10 digit account number.
+func spanishBBAN(bankID, accountNumber string) (string, error) {
+	bank, err := digits(8, false)(bankID)
+	if err != nil {
+		return "", fmt.Errorf("bank ID %q: %v", bankID, err)
+	}
+	account, err := digits(10, true)(accountNumber)
+	if err != nil {
+		return "", fmt.Errorf("account number %q: %v", accountNumber, err)
+	}
+	return fmt.Sprintf("%s%d%d%s", bank, spanishCheck("00"+bank), spanishCheck(account), account), nil
+}
+
+func spanishCheck(s string) int {
+	weights := []int{1, 2, 4, 8, 5, 10, 9, 7, 3, 6}
+	sum := 0
+	for i, c := range s {
+		sum += int(c-'0') * weights[i]
+	}
+	switch r := 11 - sum%11; r {
+	case 11:
+		return 0
+	case 10:
+		return 1
+	default:
+		return r
+	}
+}
+
+// portugueseBBAN appends the two national check digits to an 8 digit bank
+// and branch code and an 11 digit account number.
+func portugueseBBAN(bankID, accountNumber string) (string, error) {
+	s, err := concat(digits(8, false), digits(11, true))(bankID, accountNumber)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%02d", s, 98-mod97(s+"00")), nil
+}
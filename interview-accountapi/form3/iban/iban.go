@@ -0,0 +1,147 @@
+// Package iban validates, formats, parses and generates International Bank
+// Account Numbers (ISO 13616), and checks UK sort code and account number
+// pairs with the VocaLink modulus checking algorithms.
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by Parse, Validate and Generate. They are wrapped with
+// details, use errors.Is to test for them.
+var (
+	ErrFormat             = errors.New("iban: invalid format")
+	ErrLength             = errors.New("iban: invalid length")
+	ErrChecksum           = errors.New("iban: invalid check digits")
+	ErrUnsupportedCountry = errors.New("iban: unsupported country")
+)
+
+// lengths holds the IBAN length of every country of the IBAN registry.
+var lengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27,
+	"GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24,
+	"ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24, "SC": 31,
+	"SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24,
+	"TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+// IBAN is a parsed International Bank Account Number.
+type IBAN struct {
+	CountryCode string // ISO 3166-1 alpha-2 country code
+	CheckDigits string
+	BBAN        string // Basic Bank Account Number, country specific
+}
+
+// Parse parses an IBAN given in electronic or print format and checks its
+// length and check digits.
+func Parse(s string) (*IBAN, error) {
+	s = Electronic(s)
+	if len(s) < 5 || !isUpper(s[:2]) || !isDigits(s[2:4]) || !isAlnum(s[4:]) {
+		return nil, fmt.Errorf("%w: %q", ErrFormat, s)
+	}
+	country := s[:2]
+	n, ok := lengths[country]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedCountry, country)
+	}
+	if len(s) != n {
+		return nil, fmt.Errorf("%w: %v IBANs have %d characters, got %d", ErrLength, country, n, len(s))
+	}
+	if mod97(s[4:]+s[:4]) != 1 {
+		return nil, fmt.Errorf("%w: %q", ErrChecksum, s)
+	}
+	return &IBAN{CountryCode: country, CheckDigits: s[2:4], BBAN: s[4:]}, nil
+}
+
+// Validate checks that s is a valid IBAN in electronic or print format.
+func Validate(s string) error {
+	_, err := Parse(s)
+	return err
+}
+
+// String returns the IBAN in electronic format.
+func (i *IBAN) String() string {
+	return i.CountryCode + i.CheckDigits + i.BBAN
+}
+
+// Print returns the IBAN in print format, in groups of four characters.
+func (i *IBAN) Print() string {
+	return Print(i.String())
+}
+
+// Electronic returns s in electronic format: upper case, without spaces.
+func Electronic(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+// Print returns s in print format: upper case, in groups of four characters
+// separated by spaces.
+func Print(s string) string {
+	s = Electronic(s)
+	var b strings.Builder
+	for i := 0; i < len(s); i += 4 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		end := i + 4
+		if end > len(s) {
+			end = len(s)
+		}
+		b.WriteString(s[i:end])
+	}
+	return b.String()
+}
+
+// CheckDigits computes the check digits of the IBAN of the given country
+// and BBAN.
+func CheckDigits(country, bban string) string {
+	return fmt.Sprintf("%02d", 98-mod97(bban+country+"00"))
+}
+
+// mod97 returns s modulo 97, reading letters as numbers with A being 10.
+func mod97(s string) int {
+	r := 0
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			r = (r*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			r = (r*100 + int(c-'A'+10)) % 97
+		}
+	}
+	return r
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isUpper(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlnum(s string) bool {
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,120 @@
+package iban
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse("gb31 nwbk 4003 0010 0000 04")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	want := &IBAN{CountryCode: "GB", CheckDigits: "31", BBAN: "NWBK40030010000004"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse returned %+v, want %+v", got, want)
+	}
+	if s := got.String(); s != "GB31NWBK40030010000004" {
+		t.Errorf("String returned %q", s)
+	}
+	if s := got.Print(); s != "GB31 NWBK 4003 0010 0000 04" {
+		t.Errorf("Print returned %q", s)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"GB82WEST12345698765432", nil},
+		{"GB82 WEST 1234 5698 7654 32", nil},
+		{"DE89370400440532013000", nil},
+		{"FR1420041010050500013M02606", nil},
+		{"NO9386011117947", nil},
+		{"GB28NWBK40030212764204", ErrChecksum},
+		{"GB82WEST1234569876543", ErrLength},
+		{"US82WEST12345698765432", ErrUnsupportedCountry},
+		{"GB8XWEST12345698765432", ErrFormat},
+		{"GB82WEST-2345698765432", ErrFormat},
+		{"", ErrFormat},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.in)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("Validate(%q) returned %v, want %v", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestPrint(t *testing.T) {
+	for in, want := range map[string]string{
+		"GB82WEST12345698765432": "GB82 WEST 1234 5698 7654 32",
+		"be68 5390 0754 7034":    "BE68 5390 0754 7034",
+		"":                       "",
+	} {
+		if got := Print(in); got != want {
+			t.Errorf("Print(%q) returned %q, want %q", in, got, want)
+		}
+		if got, want := Electronic(in), strings.Replace(want, " ", "", -1); got != want {
+			t.Errorf("Electronic(%q) returned %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		country, bankID, account string
+		want                     string
+	}{
+		{"GB", "NWBK400300", "10000004", "GB31NWBK40030010000004"},
+		{"GB", "WEST123456", "98765432", "GB82WEST12345698765432"},
+		{"IE", "AIBK931152", "12345678", "IE29AIBK93115212345678"},
+		{"NL", "ABNA", "417164300", "NL91ABNA0417164300"},
+		{"DE", "37040044", "532013000", "DE89370400440532013000"},
+		{"AT", "19043", "234573201", "AT611904300234573201"},
+		{"CH", "00762", "11623852957", "CH9300762011623852957"},
+		{"LU", "001", "9400644750000", "LU280019400644750000"},
+		{"GR", "0110125", "0000000012300695", "GR1601101250000000012300695"},
+		{"PL", "10901014", "0000071219812874", "PL61109010140000071219812874"},
+		{"BE", "539", "0075470", "BE68539007547034"},
+		{"ES", "21000418", "0200051332", "ES9121000418450200051332"},
+		{"PT", "00020123", "12345678901", "PT50000201231234567890154"},
+	}
+	for _, tt := range tests {
+		got, err := Generate(tt.country, tt.bankID, tt.account)
+		if err != nil {
+			t.Errorf("Generate(%v, %v, %v) returned error: %v", tt.country, tt.bankID, tt.account, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Generate(%v, %v, %v) returned %v, want %v", tt.country, tt.bankID, tt.account, got, tt.want)
+		}
+		if err := Validate(got); err != nil {
+			t.Errorf("Generate(%v, %v, %v) returned invalid IBAN: %v", tt.country, tt.bankID, tt.account, err)
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		country, bankID, account string
+		want                     error
+	}{
+		{"US", "021000021", "123456", ErrUnsupportedCountry},
+		{"GB", "400300", "10000004", ErrFormat},
+		{"GB", "NWBK400300", "1000000", ErrFormat},
+		{"DE", "3704004", "532013000", ErrFormat},
+		{"DE", "37040044", "53201300000", ErrFormat},
+	}
+	for _, tt := range tests {
+		if _, err := Generate(tt.country, tt.bankID, tt.account); !errors.Is(err, tt.want) {
+			t.Errorf("Generate(%v, %v, %v) returned %v, want %v", tt.country, tt.bankID, tt.account, err, tt.want)
+		}
+	}
+	if !CanGenerate("GB") || CanGenerate("US") {
+		t.Errorf("CanGenerate reports wrong support for GB or US")
+	}
+}
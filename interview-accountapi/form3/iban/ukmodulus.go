@@ -0,0 +1,141 @@
+package iban
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Modulus checking methods of the VocaLink specification.
+const (
+	MOD10 = "MOD10"
+	MOD11 = "MOD11"
+	DBLAL = "DBLAL" // double alternate
+)
+
+// ModulusWeight is a row of the VocaLink weight table (valacdos.txt): the
+// check applied to the sort codes from Start to End.
+type ModulusWeight struct {
+	Start     string // first sort code of the range
+	End       string // last sort code of the range
+	Method    string // MOD10, MOD11 or DBLAL
+	Weights   [14]int
+	Exception int // exception rule number, 0 if none
+}
+
+// ModulusChecker checks UK sort code and account number pairs against a
+// VocaLink weight table. The weight table is published by VocaLink and is
+// not bundled, load it with ParseModulusWeights.
+//
+// Only the standard MOD10, MOD11 and DBLAL checks are performed. The
+// exception rules of the specification are not implemented, so the pairs
+// whose sort code has a row with an exception are not checked and are
+// presumed valid, rather than failing valid pairs.
+type ModulusChecker struct {
+	weights []ModulusWeight
+}
+
+// NewModulusChecker returns a checker using weights.
+func NewModulusChecker(weights []ModulusWeight) *ModulusChecker {
+	return &ModulusChecker{weights: weights}
+}
+
+// ParseModulusWeights reads a weight table in the valacdos.txt format: one
+// range per line, made of the start and end sort codes, the method, the 14
+// weights and an optional exception number, separated by spaces.
+func ParseModulusWeights(r io.Reader) ([]ModulusWeight, error) {
+	var weights []ModulusWeight
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 {
+			continue
+		}
+		if len(f) != 17 && len(f) != 18 {
+			return nil, fmt.Errorf("iban: weight table line %d: got %d fields, want 17 or 18", line, len(f))
+		}
+		w := ModulusWeight{Start: f[0], End: f[1], Method: f[2]}
+		if !isDigits(w.Start) || len(w.Start) != 6 || !isDigits(w.End) || len(w.End) != 6 {
+			return nil, fmt.Errorf("iban: weight table line %d: invalid sort code range %v-%v", line, w.Start, w.End)
+		}
+		switch w.Method {
+		case MOD10, MOD11, DBLAL:
+		default:
+			return nil, fmt.Errorf("iban: weight table line %d: unknown method %q", line, w.Method)
+		}
+		for i := range w.Weights {
+			n, err := strconv.Atoi(f[3+i])
+			if err != nil {
+				return nil, fmt.Errorf("iban: weight table line %d: invalid weight %q", line, f[3+i])
+			}
+			w.Weights[i] = n
+		}
+		if len(f) == 18 {
+			n, err := strconv.Atoi(f[17])
+			if err != nil {
+				return nil, fmt.Errorf("iban: weight table line %d: invalid exception %q", line, f[17])
+			}
+			w.Exception = n
+		}
+		weights = append(weights, w)
+	}
+	return weights, sc.Err()
+}
+
+// ErrModulusCheck is returned by Check when an account number fails the
+// modulus check of its sort code.
+var ErrModulusCheck = errors.New("iban: account number fails modulus check")
+
+// Check checks a 6 digit sort code and 8 digit account number pair. Pairs
+// whose sort code is not in the weight table, or has a row with an
+// exception, cannot be checked and are presumed valid.
+func (c *ModulusChecker) Check(sortCode, accountNumber string) error {
+	sortCode = strings.Replace(sortCode, "-", "", -1)
+	if len(sortCode) != 6 || !isDigits(sortCode) {
+		return fmt.Errorf("%w: sort code %q must have 6 digits", ErrFormat, sortCode)
+	}
+	if len(accountNumber) != 8 || !isDigits(accountNumber) {
+		return fmt.Errorf("%w: account number %q must have 8 digits", ErrFormat, accountNumber)
+	}
+
+	var rows []*ModulusWeight
+	for i := range c.weights {
+		w := &c.weights[i]
+		if sortCode < w.Start || sortCode > w.End {
+			continue
+		}
+		if w.Exception != 0 {
+			return nil
+		}
+		rows = append(rows, w)
+	}
+
+	digits := sortCode + accountNumber
+	for _, w := range rows {
+		if !w.check(digits) {
+			return fmt.Errorf("%w: %v %v (%v)", ErrModulusCheck, sortCode, accountNumber, w.Method)
+		}
+	}
+	return nil
+}
+
+// check applies the weights to the 14 digits of a sort code and account
+// number.
+func (w *ModulusWeight) check(digits string) bool {
+	sum := 0
+	for i, c := range digits {
+		p := int(c-'0') * w.Weights[i]
+		if w.Method == DBLAL {
+			// Add the digits of the products, not the products.
+			p = p/10 + p%10
+		}
+		sum += p
+	}
+	if w.Method == MOD11 {
+		return sum%11 == 0
+	}
+	return sum%10 == 0
+}
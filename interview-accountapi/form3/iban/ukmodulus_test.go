@@ -0,0 +1,114 @@
+package iban
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testWeights = `
+089000 089999 MOD10    0    0    0    0    0    0    7    1    3    7    1    3    7    1
+107000 107999 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1
+202946 202959 DBLAL    2    1    2    1    2    1    2    1    2    1    2    1    2    1
+202960 202969 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    4
+`
+
+func TestModulusChecker_Check(t *testing.T) {
+	weights, err := ParseModulusWeights(strings.NewReader(testWeights))
+	if err != nil {
+		t.Fatalf("ParseModulusWeights returned error: %v", err)
+	}
+	if len(weights) != 4 || weights[3].Exception != 4 {
+		t.Fatalf("ParseModulusWeights returned %+v", weights)
+	}
+	c := NewModulusChecker(weights)
+
+	tests := []struct {
+		sortCode, account string
+		want              error
+	}{
+		{"089999", "66374958", nil},
+		{"08-99-99", "66374958", nil},
+		{"107999", "88837491", nil},
+		{"202959", "63748472", nil},
+		{"089999", "66374959", ErrModulusCheck},
+		{"107999", "88837492", ErrModulusCheck},
+		{"202959", "63748473", ErrModulusCheck},
+		{"400300", "10000004", nil}, // not in the table
+		{"40030", "10000004", ErrFormat},
+		{"400300", "1000000", ErrFormat},
+	}
+	for _, tt := range tests {
+		err := c.Check(tt.sortCode, tt.account)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("Check(%v, %v) returned %v, want %v", tt.sortCode, tt.account, err, tt.want)
+		}
+	}
+}
+
+// testExceptionWeights are rows with exceptions for the sort codes of the
+// exception vectors of the VocaLink specification. Their weights are those
+// of the standard checks, which fail some of the vectors.
+const testExceptionWeights = `
+118765 118765 DBLAL    0    0    2    1    2    1    2    1    2    1    2    1    2    1    1
+200915 200915 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    6
+200915 200915 DBLAL    2    1    2    1    2    1    2    1    2    1    2    1    2    1    6
+309070 309070 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    2
+309070 309070 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    9
+772798 772798 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    7
+086090 086090 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    8
+938611 938611 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    5
+938600 938600 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    5
+871427 871427 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1   10
+871427 871427 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1   11
+074456 074456 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1   12
+074456 074456 MOD10    0    0    0    0    0    0    8    7    6    5    4    3    2    1   13
+180002 180002 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1   14
+`
+
+func TestModulusChecker_Check_Exceptions(t *testing.T) {
+	weights, err := ParseModulusWeights(strings.NewReader(testExceptionWeights))
+	if err != nil {
+		t.Fatalf("ParseModulusWeights returned error: %v", err)
+	}
+	c := NewModulusChecker(weights)
+
+	// Valid pairs of the VocaLink test vectors, with their exceptions.
+	tests := []struct {
+		sortCode, account string
+		exception         string
+	}{
+		{"118765", "64371389", "1"},
+		{"309070", "02355688", "2 and 9"},
+		{"309070", "12345668", "2 and 9"},
+		{"309070", "12345677", "2 and 9"},
+		{"309070", "99345694", "2 and 9"},
+		{"938611", "07806039", "5"},
+		{"938600", "42368003", "5"},
+		{"200915", "41011166", "6"},
+		{"772798", "99345694", "7"},
+		{"086090", "06774744", "8"},
+		{"871427", "46238510", "10 and 11"},
+		{"074456", "12345112", "12 and 13"},
+		{"074456", "11104102", "12 and 13"},
+		{"180002", "00000190", "14"},
+	}
+	for _, tt := range tests {
+		if err := c.Check(tt.sortCode, tt.account); err != nil {
+			t.Errorf("Check(%v, %v) with exception %v returned %v", tt.sortCode, tt.account, tt.exception, err)
+		}
+	}
+}
+
+func TestParseModulusWeights_Errors(t *testing.T) {
+	for _, in := range []string{
+		"089000 089999 MOD10 0 0 0",
+		"089000 089999 MOD12 0 0 0 0 0 0 7 1 3 7 1 3 7 1",
+		"08900 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7 1",
+		"089000 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7 x",
+	} {
+		if _, err := ParseModulusWeights(strings.NewReader(in)); err == nil {
+			t.Errorf("ParseModulusWeights(%q) returned no error", in)
+		}
+	}
+}
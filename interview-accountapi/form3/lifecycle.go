@@ -0,0 +1,154 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PaymentState is the state of a payment in its lifecycle. The API keeps no
+// such state; callers derive it from the statuses of the submission,
+// returns, reversals and recalls of the payment with SubmissionState,
+// ReturnState, ReversalState and RecallState, and use it to check which step
+// may come next:
+//
+//	created ─▶ submitted ─▶ delivered ─▶ returned
+//	               │            ├──────▶ reversed
+//	               ▼            └──────▶ recall_requested ─▶ recalled
+//	             failed                        └──────────▶ delivered (recall rejected)
+type PaymentState string
+
+const (
+	PaymentStateCreated         PaymentState = "created"
+	PaymentStateSubmitted       PaymentState = "submitted"
+	PaymentStateDelivered       PaymentState = "delivered"
+	PaymentStateFailed          PaymentState = "failed"
+	PaymentStateReturned        PaymentState = "returned"
+	PaymentStateReversed        PaymentState = "reversed"
+	PaymentStateRecallRequested PaymentState = "recall_requested"
+	PaymentStateRecalled        PaymentState = "recalled"
+)
+
+var paymentTransitions = map[PaymentState][]PaymentState{
+	PaymentStateCreated:         {PaymentStateSubmitted},
+	PaymentStateSubmitted:       {PaymentStateDelivered, PaymentStateFailed},
+	PaymentStateDelivered:       {PaymentStateReturned, PaymentStateReversed, PaymentStateRecallRequested},
+	PaymentStateRecallRequested: {PaymentStateRecalled, PaymentStateDelivered},
+}
+
+// ErrInvalidTransition is returned by PaymentState.Transition for a step the
+// lifecycle does not allow.
+var ErrInvalidTransition = errors.New("form3: invalid payment state transition")
+
+// CanTransition reports whether a payment in state s may move to state to.
+func (s PaymentState) CanTransition(to PaymentState) bool {
+	for _, t := range paymentTransitions[s] {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition returns to if a payment in state s may move to it, and an error
+// wrapping ErrInvalidTransition otherwise.
+func (s PaymentState) Transition(to PaymentState) (PaymentState, error) {
+	if !s.CanTransition(to) {
+		return s, fmt.Errorf("%w: %v to %v", ErrInvalidTransition, s, to)
+	}
+	return to, nil
+}
+
+// Final reports whether no step may follow state s.
+func (s PaymentState) Final() bool {
+	return len(paymentTransitions[s]) == 0
+}
+
+// SubmissionState returns the state of a payment given its submission:
+// delivered or failed once the submission is final, submitted before.
+func SubmissionState(s *PaymentSubmission) PaymentState {
+	if s == nil || s.Attributes == nil {
+		return PaymentStateCreated
+	}
+	switch s.Attributes.Status {
+	case PaymentSubmissionStatusDeliveryConfirmed:
+		return PaymentStateDelivered
+	case PaymentSubmissionStatusDeliveryFailed:
+		return PaymentStateFailed
+	}
+	return PaymentStateSubmitted
+}
+
+// ReturnState returns the state of a delivered payment given the submission
+// of its return: returned once the return is delivered, delivered before or
+// if the return failed.
+func ReturnState(s *PaymentSubmission) PaymentState {
+	if confirmed(s) {
+		return PaymentStateReturned
+	}
+	return PaymentStateDelivered
+}
+
+// ReversalState returns the state of a delivered payment given the
+// submission of its reversal: reversed once the reversal is delivered,
+// delivered before or if the reversal failed.
+func ReversalState(s *PaymentSubmission) PaymentState {
+	if confirmed(s) {
+		return PaymentStateReversed
+	}
+	return PaymentStateDelivered
+}
+
+// RecallState returns the state of a delivered payment given the submission
+// of its recall and the decision on it, either of which may be nil:
+// recalled or delivered again once the recall is accepted or rejected,
+// recall_requested while it is submitted and not answered, and delivered if
+// it is not submitted or failed.
+func RecallState(s *PaymentSubmission, d *RecallDecision) PaymentState {
+	if d != nil && d.Attributes != nil {
+		switch d.Attributes.Answer {
+		case RecallDecisionAccepted:
+			return PaymentStateRecalled
+		case RecallDecisionRejected:
+			return PaymentStateDelivered
+		}
+	}
+	if s == nil || s.Attributes == nil || s.Attributes.Status == PaymentSubmissionStatusDeliveryFailed {
+		return PaymentStateDelivered
+	}
+	return PaymentStateRecallRequested
+}
+
+func confirmed(s *PaymentSubmission) bool {
+	return s != nil && s.Attributes != nil && s.Attributes.Status == PaymentSubmissionStatusDeliveryConfirmed
+}
+
+// Admission statuses.
+const (
+	AdmissionStatusConfirmed = "confirmed"
+	AdmissionStatusFailed    = "failed"
+)
+
+type AdmissionAttributes struct {
+	AdmissionDatetime *time.Time `json:"admission_datetime,omitempty"`
+	SchemeStatusCode  string     `json:"scheme_status_code,omitempty"`
+	Status            string     `json:"status,omitempty"`
+	StatusReason      string     `json:"status_reason,omitempty"`
+}
+
+// Admission records that an inbound payment, return, reversal or recall was
+// admitted by the API.
+type Admission struct {
+	Attributes     *AdmissionAttributes `json:"attributes"`
+	CreatedOn      time.Time            `json:"created_on"`
+	ID             string               `json:"id"`
+	ModifiedOn     time.Time            `json:"modified_on"`
+	OrganisationID string               `json:"organisation_id"`
+	Type           string               `json:"type"`
+	Version        int                  `json:"version"`
+}
+
+type AdmissionResponse struct {
+	Data  *Admission    `json:"data"`
+	Links *PaymentLinks `json:"links"`
+}
@@ -0,0 +1,177 @@
+package form3
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPaymentState_Transition(t *testing.T) {
+	tests := []struct {
+		from, to PaymentState
+		ok       bool
+	}{
+		{PaymentStateCreated, PaymentStateSubmitted, true},
+		{PaymentStateCreated, PaymentStateDelivered, false},
+		{PaymentStateSubmitted, PaymentStateDelivered, true},
+		{PaymentStateSubmitted, PaymentStateFailed, true},
+		{PaymentStateSubmitted, PaymentStateReturned, false},
+		{PaymentStateDelivered, PaymentStateReturned, true},
+		{PaymentStateDelivered, PaymentStateReversed, true},
+		{PaymentStateDelivered, PaymentStateRecallRequested, true},
+		{PaymentStateRecallRequested, PaymentStateRecalled, true},
+		{PaymentStateRecallRequested, PaymentStateDelivered, true},
+		{PaymentStateFailed, PaymentStateSubmitted, false},
+		{PaymentStateReturned, PaymentStateReversed, false},
+		{PaymentStateRecalled, PaymentStateReturned, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.from.Transition(tt.to)
+		if tt.ok {
+			if err != nil || got != tt.to {
+				t.Errorf("%v.Transition(%v) = %v, %v", tt.from, tt.to, got, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidTransition) || got != tt.from {
+			t.Errorf("%v.Transition(%v) = %v, %v, want %v", tt.from, tt.to, got, err, ErrInvalidTransition)
+		}
+	}
+}
+
+func TestPaymentState_Final(t *testing.T) {
+	for _, s := range []PaymentState{PaymentStateFailed, PaymentStateReturned, PaymentStateReversed, PaymentStateRecalled} {
+		if !s.Final() {
+			t.Errorf("%v is not final", s)
+		}
+	}
+	for _, s := range []PaymentState{PaymentStateCreated, PaymentStateSubmitted, PaymentStateDelivered, PaymentStateRecallRequested} {
+		if s.Final() {
+			t.Errorf("%v is final", s)
+		}
+	}
+}
+
+func TestSubmissionState(t *testing.T) {
+	tests := map[string]PaymentState{
+		"":                   PaymentStateCreated,
+		"accepted":           PaymentStateSubmitted,
+		"submitted":          PaymentStateSubmitted,
+		"delivery_confirmed": PaymentStateDelivered,
+		"delivery_failed":    PaymentStateFailed,
+	}
+	for status, want := range tests {
+		var s *PaymentSubmission
+		if status != "" {
+			s = &PaymentSubmission{Attributes: &PaymentSubmissionAttributes{Status: status}}
+		}
+		if got := SubmissionState(s); got != want {
+			t.Errorf("SubmissionState(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func submission(status string) *PaymentSubmission {
+	return &PaymentSubmission{Attributes: &PaymentSubmissionAttributes{Status: status}}
+}
+
+func TestReturnState(t *testing.T) {
+	tests := []struct {
+		submission *PaymentSubmission
+		want       PaymentState
+	}{
+		{nil, PaymentStateDelivered},
+		{submission("submitted"), PaymentStateDelivered},
+		{submission("delivery_failed"), PaymentStateDelivered},
+		{submission("delivery_confirmed"), PaymentStateReturned},
+	}
+	for _, tt := range tests {
+		got := ReturnState(tt.submission)
+		if got != tt.want {
+			t.Errorf("ReturnState(%+v) = %v, want %v", tt.submission, got, tt.want)
+		}
+		if got != PaymentStateDelivered && !PaymentStateDelivered.CanTransition(got) {
+			t.Errorf("ReturnState(%+v) = %v, not reachable from delivered", tt.submission, got)
+		}
+	}
+}
+
+func TestReversalState(t *testing.T) {
+	tests := []struct {
+		submission *PaymentSubmission
+		want       PaymentState
+	}{
+		{nil, PaymentStateDelivered},
+		{submission("submitted"), PaymentStateDelivered},
+		{submission("delivery_failed"), PaymentStateDelivered},
+		{submission("delivery_confirmed"), PaymentStateReversed},
+	}
+	for _, tt := range tests {
+		got := ReversalState(tt.submission)
+		if got != tt.want {
+			t.Errorf("ReversalState(%+v) = %v, want %v", tt.submission, got, tt.want)
+		}
+		if got != PaymentStateDelivered && !PaymentStateDelivered.CanTransition(got) {
+			t.Errorf("ReversalState(%+v) = %v, not reachable from delivered", tt.submission, got)
+		}
+	}
+}
+
+func TestRecallState(t *testing.T) {
+	decision := func(answer string) *RecallDecision {
+		return &RecallDecision{Attributes: &RecallDecisionAttributes{Answer: answer}}
+	}
+	tests := []struct {
+		submission *PaymentSubmission
+		decision   *RecallDecision
+		want       PaymentState
+	}{
+		{nil, nil, PaymentStateDelivered},
+		{submission("delivery_failed"), nil, PaymentStateDelivered},
+		{submission("submitted"), nil, PaymentStateRecallRequested},
+		{submission("delivery_confirmed"), nil, PaymentStateRecallRequested},
+		{submission("delivery_confirmed"), decision(RecallDecisionAccepted), PaymentStateRecalled},
+		{submission("delivery_confirmed"), decision(RecallDecisionRejected), PaymentStateDelivered},
+		{nil, decision(RecallDecisionAccepted), PaymentStateRecalled}, // inbound recall
+	}
+	for _, tt := range tests {
+		if got := RecallState(tt.submission, tt.decision); got != tt.want {
+			t.Errorf("RecallState(%+v, %+v) = %v, want %v", tt.submission, tt.decision, got, tt.want)
+		}
+	}
+}
+
+// TestPaymentState_Lifecycle drives a payment through its lifecycle with the
+// states derived from its records, checking every step.
+func TestPaymentState_Lifecycle(t *testing.T) {
+	recall := submission("delivery_confirmed")
+	steps := []PaymentState{
+		SubmissionState(submission("accepted")),
+		SubmissionState(submission("delivery_confirmed")),
+		RecallState(recall, nil),
+		RecallState(recall, &RecallDecision{Attributes: &RecallDecisionAttributes{Answer: RecallDecisionRejected}}),
+		RecallState(submission("submitted"), nil),
+		RecallState(recall, &RecallDecision{Attributes: &RecallDecisionAttributes{Answer: RecallDecisionAccepted}}),
+	}
+	want := []PaymentState{PaymentStateSubmitted, PaymentStateDelivered, PaymentStateRecallRequested,
+		PaymentStateDelivered, PaymentStateRecallRequested, PaymentStateRecalled}
+
+	state := SubmissionState(nil)
+	for i, next := range steps {
+		var err error
+		if state, err = state.Transition(next); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		if state != want[i] {
+			t.Fatalf("step %d: state %v, want %v", i, state, want[i])
+		}
+	}
+	if !state.Final() {
+		t.Errorf("state %v is not final", state)
+	}
+
+	for _, s := range []PaymentState{ReturnState(submission("delivery_confirmed")), ReversalState(submission("delivery_confirmed"))} {
+		if _, err := PaymentStateDelivered.Transition(s); err != nil {
+			t.Errorf("Transition from delivered: %v", err)
+		}
+	}
+}
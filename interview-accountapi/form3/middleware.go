@@ -0,0 +1,78 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+)
+
+// RoundTripFunc sends a request made by Client.Do and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the RoundTripFunc sending the requests of a client, e.g.
+// to add headers to the requests or to log them with their responses. It
+// may replace the request, e.g. with req.Clone, and must return either a
+// response or an error.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds middleware to the client. The first middleware added is the
+// outermost. A middleware is called once per call of Do: retries, rate
+// limiting, circuit breaking, authentication and signing happen inside the
+// chain. A middleware thus runs before the request is signed, but only the
+// headers configured in the signer, httpsig.DefaultHeaders by default, are
+// signed, so other headers set by a middleware are not. The name of the
+// service operation of the request is returned by Operation(req.Context()).
+//
+// Use must not be called concurrently with requests.
+func (c *Client) Use(mw ...Middleware) {
+	for _, m := range mw {
+		if m != nil {
+			c.middleware = append(c.middleware, m)
+		}
+	}
+}
+
+// WithMiddleware adds middleware to the client, see Client.Use.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) error {
+		c.Use(mw...)
+		return nil
+	}
+}
+
+// roundTrip sends req through the middleware chain of the client.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	rt := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return c.sendAuthorized(req.Context(), req)
+	})
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	return rt(req)
+}
+
+type operationKey struct{}
+
+// WithOperation returns a copy of ctx carrying the name of the service
+// operation of a request, e.g. "Account.Create". The service methods name
+// their requests themselves; requests made with NewRequest and Do directly
+// may be named with it. A name set by the caller is kept by the services.
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// withOperation names the requests sent with ctx by a service method, unless
+// ctx already carries the name of an outer operation, e.g. of
+// Account.CreateOrGet for the Account.Create it calls.
+func withOperation(ctx context.Context, name string) context.Context {
+	if ctx == nil || Operation(ctx) != "" {
+		return ctx
+	}
+	return WithOperation(ctx, name)
+}
+
+// Operation returns the name of the service operation carried by ctx, or ""
+// if it has none.
+func Operation(ctx context.Context) string {
+	name, _ := ctx.Value(operationKey{}).(string)
+	return name
+}
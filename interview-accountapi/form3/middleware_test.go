@@ -0,0 +1,127 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_Use(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+Operation(req.Context()))
+				req.Header.Add("X-Trace", name)
+				resp, err := next(req)
+				calls = append(calls, name+" done")
+				return resp, err
+			}
+		}
+	}
+	client.Use(trace("outer"), nil, trace("inner"))
+
+	mux.HandleFunc("/v1/organisation/accounts/a", func(w http.ResponseWriter, r *http.Request) {
+		if got := strings.Join(r.Header["X-Trace"], ","); got != "outer,inner" {
+			t.Errorf("X-Trace = %q, want outer,inner", got)
+		}
+		fmt.Fprint(w, `{"data":{"id":"a"}}`)
+	})
+
+	if _, _, _, err := client.Account.Fetch(context.Background(), "a"); err != nil {
+		t.Fatalf("Account.Fetch returned error: %v", err)
+	}
+	want := []string{"outer Account.Fetch", "inner Account.Fetch", "inner done", "outer done"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Middleware calls = %q, want %q", calls, want)
+	}
+}
+
+func TestClient_Use_Operation(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var ops []string
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			ops = append(ops, Operation(req.Context()))
+			return next(req)
+		}
+	})
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[{"id":"a"}]}`)
+	})
+	mux.HandleFunc("/v1/transaction/payments/p/returns/r/submissions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"s"}}`)
+	})
+	mux.HandleFunc("/v1/organisation/units/o", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"o"}}`)
+	})
+
+	ctx := context.Background()
+	it := client.Account.ListAll(ctx, nil)
+	for it.Next() {
+	}
+	client.Account.List(ctx, nil)
+	client.Return.CreateSubmission(ctx, "p", "r", "s", "o")
+	client.Organisation.Fetch(WithOperation(ctx, "Custom.Fetch"), "o")
+	req, _ := client.NewRequest("GET", "/v1/organisation/units/o", nil)
+	client.Do(ctx, req, nil)
+
+	want := []string{"Account.ListAll", "Account.List", "Return.CreateSubmission", "Custom.Fetch", ""}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("Operations = %q, want %q", ops, want)
+	}
+}
+
+func TestClient_Use_NoResponse(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) { return nil, nil }
+	})
+
+	if _, _, _, err := client.Account.Fetch(context.Background(), "a"); err == nil {
+		t.Errorf("Account.Fetch through a middleware returning nothing returned no error")
+	}
+}
+
+func TestClient_Use_NestedOperation(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var ops []string
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			ops = append(ops, req.Method+" "+Operation(req.Context()))
+			return next(req)
+		}
+	})
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error_message":"duplicate"}`)
+	})
+	mux.HandleFunc("/v1/organisation/accounts/a", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"a","organisation_id":"o"}}`)
+	})
+	mux.HandleFunc("/v1/notification/subscriptions/s", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"s"}}`)
+	})
+
+	ctx := context.Background()
+	client.Account.CreateOrGet(ctx, "a", "o", &AccountCreateRequestAttributes{Country: "GB"})
+	client.Subscription.Deactivate(ctx, "s", 0)
+
+	want := []string{"POST Account.CreateOrGet", "GET Account.CreateOrGet", "PATCH Subscription.Deactivate"}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("Operations = %q, want %q", ops, want)
+	}
+}
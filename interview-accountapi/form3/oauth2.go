@@ -0,0 +1,159 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenPath is relative, so that a path prefix of BaseURL is kept.
+	tokenPath = "v1/oauth2/token"
+
+	// A token is refreshed this long before it expires, so that it does not
+	// expire while a request is in flight.
+	tokenExpiryDelta = 30 * time.Second
+)
+
+// accessToken is an OAuth2 access token returned by the API.
+type accessToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"` // seconds
+
+	expiry time.Time // zero if the API did not tell
+}
+
+// WithClientCredentials makes the client authenticate with bearer tokens
+// obtained from /v1/oauth2/token with the OAuth2 client credentials grant.
+// A token is cached and shared by concurrent requests until shortly before
+// it expires. A request rejected with 401 Unauthorized is sent once more
+// with a new token. It cannot be combined with WithSigning, which sets the
+// Authorization header too: NewClient returns an error.
+func WithClientCredentials(clientID, clientSecret string) ClientOption {
+	return func(c *Client) error {
+		if clientID == "" || clientSecret == "" {
+			return errors.New("client ID and client secret are required")
+		}
+		c.tokens = &tokenSource{client: c, clientID: clientID, clientSecret: clientSecret, now: time.Now}
+		return nil
+	}
+}
+
+// tokenSource obtains and caches the access tokens of a client.
+type tokenSource struct {
+	client       *Client
+	clientID     string
+	clientSecret string
+	now          func() time.Time
+
+	mu    sync.Mutex // held while a token is obtained, so that only one request does it
+	token *accessToken
+}
+
+// get returns a valid token, from the cache if possible. If stale is the
+// access token of the cached token, e.g. because the API rejected it, a new
+// token is obtained instead. Concurrent callers wait for a single new token.
+func (s *tokenSource) get(ctx context.Context, stale string) (*accessToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t := s.token; t != nil && t.AccessToken != stale &&
+		(t.expiry.IsZero() || s.now().Before(t.expiry.Add(-tokenExpiryDelta))) {
+		return t, nil
+	}
+
+	t, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.token = t
+	return t, nil
+}
+
+// fetch obtains a new token from the API.
+func (s *tokenSource) fetch(ctx context.Context) (*accessToken, error) {
+	u, err := s.client.BaseURL.Parse(tokenPath)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequest("POST", u.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.client.UserAgent != "" {
+		req.Header.Set("User-Agent", s.client.UserAgent)
+	}
+
+	resp, err := s.client.client.Do(withContext(ctx, req))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	t := new(accessToken)
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return nil, err
+	}
+	if t.AccessToken == "" {
+		return nil, errors.New("token response has no access token")
+	}
+	if t.ExpiresIn > 0 {
+		t.expiry = s.now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+	return t, nil
+}
+
+// authorize sets the Authorization header of req to the bearer token of
+// the client.
+func (s *tokenSource) authorize(ctx context.Context, req *http.Request) error {
+	t, err := s.get(ctx, "")
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.AccessToken)
+	return nil
+}
+
+// sendAuthorized sends req with send. If the API rejects the token of the
+// client with 401 Unauthorized, a new token is obtained and req is sent once
+// more.
+func (c *Client) sendAuthorized(ctx context.Context, req *http.Request) (*http.Response, error) {
+	resp, err := c.send(ctx, req)
+	if c.tokens == nil || err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			// The body has been consumed and cannot be sent again.
+			return resp, nil
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		req.Body = body
+	}
+
+	stale := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if _, err := c.tokens.get(ctx, stale); err != nil {
+		return resp, nil
+	}
+	_, _ = io.CopyN(ioutil.Discard, resp.Body, maxBodySlurpSize)
+	_ = resp.Body.Close()
+	return c.send(ctx, req)
+}
@@ -0,0 +1,178 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// handleToken serves tokens "token-1", "token-2"... expiring after an hour,
+// and returns the number of tokens served.
+func handleToken(t *testing.T, mux *http.ServeMux) func() int {
+	var n int32
+	mux.HandleFunc(baseURLPath+"/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Content-Type", "application/x-www-form-urlencoded")
+		if id, secret, ok := r.BasicAuth(); !ok || id != "id" || secret != "secret" {
+			t.Errorf("Token request has basic auth %q %q", id, secret)
+		}
+		if got := r.FormValue("grant_type"); got != "client_credentials" {
+			t.Errorf("Token request has grant_type %q", got)
+		}
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600}`, atomic.AddInt32(&n, 1))
+	})
+	return func() int { return int(atomic.LoadInt32(&n)) }
+}
+
+func setupOAuth2(t *testing.T) (*Client, *http.ServeMux, func()) {
+	client, mux, _, teardown := setup()
+	if err := WithClientCredentials("id", "secret")(client); err != nil {
+		t.Fatalf("WithClientCredentials returned error: %v", err)
+	}
+	return client, mux, teardown
+}
+
+func TestClientCredentials_CachesToken(t *testing.T) {
+	client, mux, teardown := setupOAuth2(t)
+	defer teardown()
+	tokens := handleToken(t, mux)
+
+	var auth []string
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	now := time.Now()
+	client.tokens.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if _, _, _, err := client.Account.List(context.Background(), nil); err != nil {
+			t.Fatalf("Account.List returned error: %v", err)
+		}
+	}
+	if tokens() != 1 || auth[0] != "Bearer token-1" || auth[1] != "Bearer token-1" {
+		t.Errorf("Requests sent with %v after %d token requests, want one token", auth, tokens())
+	}
+
+	// Shortly before expiry the token is refreshed.
+	now = now.Add(time.Hour - tokenExpiryDelta)
+	if _, _, _, err := client.Account.List(context.Background(), nil); err != nil {
+		t.Fatalf("Account.List returned error: %v", err)
+	}
+	if tokens() != 2 || auth[2] != "Bearer token-2" {
+		t.Errorf("Request sent with %v after %d token requests, want a new token", auth[2], tokens())
+	}
+}
+
+func TestClientCredentials_RetriesOnceOnUnauthorized(t *testing.T) {
+	client, mux, teardown := setupOAuth2(t)
+	defer teardown()
+	tokens := handleToken(t, mux)
+
+	var auth []string
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error_message":"invalid token"}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"a68eddcd-6eec-4b5e-846d-97b1161248e2"}}`)
+	})
+
+	_, _, _, err := client.Account.Create(context.Background(),
+		"a68eddcd-6eec-4b5e-846d-97b1161248e2", "d91afcdb-62d2-4185-b23d-71c98eaab812", attr)
+	if err != nil {
+		t.Fatalf("Account.Create returned error: %v", err)
+	}
+	if tokens() != 2 || len(auth) != 2 || auth[1] != "Bearer token-2" {
+		t.Errorf("Requests sent with %v after %d token requests", auth, tokens())
+	}
+
+	// A token rejected again is not refreshed twice.
+	client.tokens.token.AccessToken = "revoked"
+	auth = nil
+	_, _, resp, err := client.Account.Create(context.Background(),
+		"a68eddcd-6eec-4b5e-846d-97b1161248e2", "d91afcdb-62d2-4185-b23d-71c98eaab812", attr)
+	if err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Account.Create returned %v, want 401", err)
+	}
+	if len(auth) != 2 {
+		t.Errorf("Server received %d requests, want 2", len(auth))
+	}
+}
+
+func TestClientCredentials_ConcurrentRequests(t *testing.T) {
+	client, mux, teardown := setupOAuth2(t)
+	defer teardown()
+	tokens := handleToken(t, mux)
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "Authorization", "Bearer token-1")
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, _, err := client.Account.List(context.Background(), nil); err != nil {
+				t.Errorf("Account.List returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if tokens() != 1 {
+		t.Errorf("Client requested %d tokens, want 1", tokens())
+	}
+}
+
+func TestClientCredentials_BaseURLPrefix(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var path string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, `{"access_token":"token","token_type":"bearer","expires_in":3600}`)
+	})
+
+	client, err := NewClient(WithBaseURL(server.URL+"/form3/api/"), WithClientCredentials("id", "secret"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if _, err := client.tokens.get(context.Background(), ""); err != nil {
+		t.Fatalf("Token request returned error: %v", err)
+	}
+	if want := "/form3/api/v1/oauth2/token"; path != want {
+		t.Errorf("Token requested at %q, want %q", path, want)
+	}
+}
+
+func TestClientCredentials_TokenError(t *testing.T) {
+	client, mux, teardown := setupOAuth2(t)
+	defer teardown()
+	mux.HandleFunc(baseURLPath+"/v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error_message":"invalid client"}`)
+	})
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request sent without a token")
+	})
+
+	_, _, _, err := client.Account.List(context.Background(), nil)
+	if !IsValidation(err) {
+		t.Errorf("Account.List returned %v, want the token error", err)
+	}
+
+	if _, err := NewClient(WithClientCredentials("id", "")); err == nil {
+		t.Errorf("NewClient without client secret returned no error")
+	}
+}
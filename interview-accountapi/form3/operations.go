@@ -8,22 +8,67 @@ import (
 
 type AccountService service
 
+// Account classifications.
+const (
+	AccountClassificationPersonal = "Personal"
+	AccountClassificationBusiness = "Business"
+)
+
+// Account statuses.
+const (
+	AccountStatusPending   = "pending"
+	AccountStatusConfirmed = "confirmed"
+	AccountStatusFailed    = "failed"
+	AccountStatusClosed    = "closed"
+)
+
+// Account validation types.
+const (
+	AccountValidationTypeCard = "card"
+	AccountValidationTypeNone = "none"
+)
+
 type AccountAttributes struct {
-	AccountClassification       string      `json:"account_classification"`
-	AccountNumber               string      `json:"account_number"`
-	AlternativeBankAccountNames interface{} `json:"alternative_bank_account_names"`
-	BankID                      string      `json:"bank_id"`
-	BankIDCode                  string      `json:"bank_id_code"`
-	BaseCurrency                string      `json:"base_currency"`
-	Bic                         string      `json:"bic"`
-	Country                     string      `json:"country"`
-	CustomerID                  string      `json:"customer_id"`
-	Iban                        string      `json:"iban"`
-	JointAccount                bool        `json:"joint_account"`
-	Switched                    string      `json:"switched"`
-	SecondaryIdentification     string      `json:"secondary_identification"`
-	AccountMatchingOptOut       bool        `json:"account_matching_opt_out"`
-	AlternativeNames            bool        `json:"alternative_names"`
+	AccountClassification       string                 `json:"account_classification,omitempty"`
+	AccountMatchingOptOut       bool                   `json:"account_matching_opt_out,omitempty"`
+	AccountNumber               string                 `json:"account_number,omitempty"`
+	AcceptanceQualifier         string                 `json:"acceptance_qualifier,omitempty"`
+	AlternativeBankAccountNames []string               `json:"alternative_bank_account_names,omitempty"`
+	AlternativeNames            []string               `json:"alternative_names,omitempty"`
+	BankID                      string                 `json:"bank_id,omitempty"`
+	BankIDCode                  string                 `json:"bank_id_code,omitempty"`
+	BaseCurrency                string                 `json:"base_currency,omitempty"`
+	Bic                         string                 `json:"bic,omitempty"`
+	Country                     string                 `json:"country"`
+	CustomerID                  string                 `json:"customer_id,omitempty"`
+	Iban                        string                 `json:"iban,omitempty"`
+	JointAccount                bool                   `json:"joint_account,omitempty"`
+	Name                        []string               `json:"name,omitempty"`
+	PrivateIdentification       *PrivateIdentification `json:"private_identification,omitempty"`
+	ProcessingService           string                 `json:"processing_service,omitempty"`
+	ReferenceMask               string                 `json:"reference_mask,omitempty"`
+	SecondaryIdentification     string                 `json:"secondary_identification,omitempty"`
+	Status                      string                 `json:"status,omitempty"`
+	StatusReason                string                 `json:"status_reason,omitempty"`
+	Switched                    string                 `json:"switched,omitempty"`
+	UserDefinedData             []*UserDefinedData     `json:"user_defined_data,omitempty"`
+	ValidationType              string                 `json:"validation_type,omitempty"`
+}
+
+// PrivateIdentification identifies the person holding a personal account.
+type PrivateIdentification struct {
+	Address        []string `json:"address,omitempty"`
+	BirthCountry   string   `json:"birth_country,omitempty"`
+	BirthDate      string   `json:"birth_date,omitempty"` // formatted as YYYY-MM-DD
+	City           string   `json:"city,omitempty"`
+	Country        string   `json:"country,omitempty"`
+	Identification string   `json:"identification,omitempty"`
+}
+
+// UserDefinedData is a key-value pair stored with an account.
+type UserDefinedData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 type Account struct {
@@ -39,6 +84,8 @@ type Account struct {
 type AccountListLinks struct {
 	First string `json:"first"`
 	Last  string `json:"last"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
 	Self  string `json:"self"`
 }
 
@@ -66,21 +113,35 @@ type AccountCreateResponse struct {
 }
 
 // Request
+
+// AccountCreateRequestAttributes holds the attributes of a new account. Only
+// Country is required by the API, the other attributes are omitted when
+// unset. Name holds up to four lines of the account holder's name and
+// AlternativeNames up to three alternative names.
 type AccountCreateRequestAttributes struct {
-	AccountClassification   string `json:"account_classification"`
-	AccountNumber           string `json:"account_number"`
-	BankID                  string `json:"bank_id"`
-	BankIDCode              string `json:"bank_id_code"`
-	BaseCurrency            string `json:"base_currency"`
-	Bic                     string `json:"bic"`
-	Country                 string `json:"country"`
-	CustomerID              string `json:"customer_id"`
-	Iban                    string `json:"iban"`
-	JointAccount            bool   `json:"joint_account"`
-	Switched                string `json:"switched"`
-	SecondaryIdentification string `json:"secondary_identification"`
-	AccountMatchingOptOut   bool   `json:"account_matching_opt_out"`
-	AlternativeNames        bool   `json:"alternative_names"`
+	AccountClassification   string                 `json:"account_classification,omitempty"`
+	AccountMatchingOptOut   *bool                  `json:"account_matching_opt_out,omitempty"`
+	AccountNumber           string                 `json:"account_number,omitempty"`
+	AcceptanceQualifier     string                 `json:"acceptance_qualifier,omitempty"`
+	AlternativeNames        []string               `json:"alternative_names,omitempty"`
+	BankID                  string                 `json:"bank_id,omitempty"`
+	BankIDCode              string                 `json:"bank_id_code,omitempty"`
+	BaseCurrency            string                 `json:"base_currency,omitempty"`
+	Bic                     string                 `json:"bic,omitempty"`
+	Country                 string                 `json:"country"`
+	CustomerID              string                 `json:"customer_id,omitempty"`
+	Iban                    string                 `json:"iban,omitempty"`
+	JointAccount            *bool                  `json:"joint_account,omitempty"`
+	Name                    []string               `json:"name,omitempty"`
+	PrivateIdentification   *PrivateIdentification `json:"private_identification,omitempty"`
+	ProcessingService       string                 `json:"processing_service,omitempty"`
+	ReferenceMask           string                 `json:"reference_mask,omitempty"`
+	SecondaryIdentification string                 `json:"secondary_identification,omitempty"`
+	Status                  string                 `json:"status,omitempty"`
+	StatusReason            string                 `json:"status_reason,omitempty"`
+	Switched                string                 `json:"switched,omitempty"`
+	UserDefinedData         []*UserDefinedData     `json:"user_defined_data,omitempty"`
+	ValidationType          string                 `json:"validation_type,omitempty"`
 }
 
 type AccountCreateRequestData struct {
@@ -94,8 +155,66 @@ type AccountCreateRequest struct {
 	Data *AccountCreateRequestData `json:"data"`
 }
 
+// AccountUpdateRequestAttributes holds the attributes changed by an update.
+// Nil fields are left unchanged.
+type AccountUpdateRequestAttributes struct {
+	AccountClassification   *string            `json:"account_classification,omitempty"`
+	AccountMatchingOptOut   *bool              `json:"account_matching_opt_out,omitempty"`
+	AccountNumber           *string            `json:"account_number,omitempty"`
+	AcceptanceQualifier     *string            `json:"acceptance_qualifier,omitempty"`
+	AlternativeNames        []string           `json:"alternative_names,omitempty"`
+	BankID                  *string            `json:"bank_id,omitempty"`
+	BankIDCode              *string            `json:"bank_id_code,omitempty"`
+	BaseCurrency            *string            `json:"base_currency,omitempty"`
+	Bic                     *string            `json:"bic,omitempty"`
+	CustomerID              *string            `json:"customer_id,omitempty"`
+	Iban                    *string            `json:"iban,omitempty"`
+	JointAccount            *bool              `json:"joint_account,omitempty"`
+	Name                    []string           `json:"name,omitempty"`
+	ProcessingService       *string            `json:"processing_service,omitempty"`
+	ReferenceMask           *string            `json:"reference_mask,omitempty"`
+	SecondaryIdentification *string            `json:"secondary_identification,omitempty"`
+	Status                  *string            `json:"status,omitempty"`
+	StatusReason            *string            `json:"status_reason,omitempty"`
+	Switched                *string            `json:"switched,omitempty"`
+	UserDefinedData         []*UserDefinedData `json:"user_defined_data,omitempty"`
+	ValidationType          *string            `json:"validation_type,omitempty"`
+}
+
+type AccountUpdateRequestData struct {
+	Attributes *AccountUpdateRequestAttributes `json:"attributes"`
+	ID         string                          `json:"id"`
+	Type       string                          `json:"type"`
+	Version    int                             `json:"version"`
+}
+
+type AccountUpdateRequest struct {
+	Data *AccountUpdateRequestData `json:"data"`
+}
+
+type AccountUpdateLinks struct {
+	Self string `json:"self"`
+}
+
+type AccountUpdateResponse struct {
+	Data  *Account            `json:"data"`
+	Links *AccountUpdateLinks `json:"links"`
+}
+
+// Create creates an account. If the client was created with
+// WithRequestValidation, the request is validated first and FieldErrors are
+// returned without calling the API when it is invalid.
+//
 // Form3 API docs: https://api-docs.form3.tech/api.html#organisation-accounts-create
 func (s *AccountService) Create(ctx context.Context, id string, organizationId string, attributes *AccountCreateRequestAttributes) (*Account, *AccountCreateLinks, *Response, error) {
+	ctx = withOperation(ctx, "Account.Create")
+
+	if s.client.validateRequests {
+		if err := validateAccountCreate(id, organizationId, attributes); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
 	req, err := s.client.NewRequest("POST", "/v1/organisation/accounts",
 		&AccountCreateRequest{&AccountCreateRequestData{
 			attributes,
@@ -118,8 +237,48 @@ func (s *AccountService) Create(ctx context.Context, id string, organizationId s
 	return account, links, resp, nil
 }
 
+// CreateOrGet creates an account like Create, and may be retried safely
+// when it is not known whether an earlier attempt succeeded, e.g. after a
+// timeout. If an account with the given id exists already, it is fetched
+// and returned when it has the requested organisation and attributes, with
+// a response with status 200 instead of 201. Otherwise an
+// *AccountMismatchError listing the differing fields is returned.
+// Attributes not set in the request, e.g. an IBAN generated by the API, are
+// not compared.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-accounts-create
+func (s *AccountService) CreateOrGet(ctx context.Context, id string, organizationId string, attributes *AccountCreateRequestAttributes) (*Account, *AccountCreateLinks, *Response, error) {
+	ctx = withOperation(ctx, "Account.CreateOrGet")
+
+	account, links, resp, err := s.Create(ctx, id, organizationId, attributes)
+	if !IsConflict(err) {
+		return account, links, resp, err
+	}
+	conflict := err
+
+	account, fetchLinks, resp, err := s.Fetch(ctx, id)
+	if IsNotFound(err) {
+		// The conflict is not caused by the ID, but e.g. by another unique
+		// attribute.
+		return nil, nil, resp, conflict
+	}
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	if fields := accountDiff(organizationId, attributes, account); len(fields) > 0 {
+		return nil, nil, resp, &AccountMismatchError{Account: account, Fields: fields, Err: conflict}
+	}
+	if fetchLinks != nil {
+		links = &AccountCreateLinks{Self: fetchLinks.Self}
+	}
+	return account, links, resp, nil
+}
+
 // Form3 API docs: https://api-docs.form3.tech/api.html#organisation-accounts-fetch
 func (s *AccountService) Fetch(ctx context.Context, id string) (*Account, *AccountFetchLinks, *Response, error) {
+	ctx = withOperation(ctx, "Account.Fetch")
+
 	u := fmt.Sprintf("/v1/organisation/accounts/%s", id)
 
 	req, err := s.client.NewRequest("GET", u, nil)
@@ -140,7 +299,9 @@ func (s *AccountService) Fetch(ctx context.Context, id string) (*Account, *Accou
 }
 
 // Form3 API docs: https://api-docs.form3.tech/api.html#organisation-accounts-list
-func (s *AccountService) List(ctx context.Context, opts *ListOptions) ([]*Account, *AccountListLinks, *Response, error) {
+func (s *AccountService) List(ctx context.Context, opts *AccountListOptions) ([]*Account, *AccountListLinks, *Response, error) {
+	ctx = withOperation(ctx, "Account.List")
+
 	var u string
 	u = "/v1/organisation/accounts"
 	u, err := addOptions(u, opts)
@@ -148,6 +309,11 @@ func (s *AccountService) List(ctx context.Context, opts *ListOptions) ([]*Accoun
 		return nil, nil, nil, err
 	}
 
+	return s.list(ctx, u)
+}
+
+// list fetches the page of accounts at u.
+func (s *AccountService) list(ctx context.Context, u string) ([]*Account, *AccountListLinks, *Response, error) {
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, nil, nil, err
@@ -161,12 +327,48 @@ func (s *AccountService) List(ctx context.Context, opts *ListOptions) ([]*Accoun
 
 	accounts := apiResp.Data
 	links := apiResp.Links
+	resp.populatePageValues((*ListLinks)(links))
 
 	return accounts, links, resp, nil
 }
 
+// Update changes the attributes set in patch on the account with the given
+// id. version must be the current version of the account, otherwise the
+// update is rejected with a *ConflictError. The returned account carries
+// the new version.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-accounts-patch
+func (s *AccountService) Update(ctx context.Context, id string, version int, patch *AccountUpdateRequestAttributes) (*Account, *AccountUpdateLinks, *Response, error) {
+	ctx = withOperation(ctx, "Account.Update")
+
+	u := fmt.Sprintf("/v1/organisation/accounts/%s", id)
+
+	req, err := s.client.NewRequest("PATCH", u,
+		&AccountUpdateRequest{&AccountUpdateRequestData{
+			patch,
+			id,
+			"accounts",
+			version}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *AccountUpdateResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	account := r.Data
+	links := r.Links
+
+	return account, links, resp, nil
+}
+
 // Form3 API docs: https://api-docs.form3.tech/api.html#organisation-accounts-delete
 func (s *AccountService) Delete(ctx context.Context, id string, version int) (*Response, error) {
+	ctx = withOperation(ctx, "Account.Delete")
+
 	u := fmt.Sprintf("/v1/organisation/accounts/%s?version=%d", id, version)
 	req, err := s.client.NewRequest("DELETE", u, nil)
 	if err != nil {
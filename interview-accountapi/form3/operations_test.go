@@ -17,9 +17,7 @@ const baseURLPath = "/localhost:8080"
 func setup() (client *Client, mux *http.ServeMux, serverURL string, teardown func()) {
 	mux = http.NewServeMux()
 	server := httptest.NewServer(mux)
-	client = NewClient(nil)
-	u, _ := url.Parse(server.URL + baseURLPath + "/")
-	client.BaseURL = u
+	client, _ = NewClient(WithBaseURL(server.URL + baseURLPath + "/"))
 	return client, mux, server.URL, server.Close
 }
 
@@ -30,6 +28,21 @@ func testMethod(t *testing.T, r *http.Request, want string) {
 	}
 }
 
+type values map[string]string
+
+func testFormValues(t *testing.T, r *http.Request, values values) {
+	t.Helper()
+	want := url.Values{}
+	for k, v := range values {
+		want.Set(k, v)
+	}
+
+	r.ParseForm()
+	if got := r.Form; !reflect.DeepEqual(got, want) {
+		t.Errorf("Request parameters: %v, want %v", got, want)
+	}
+}
+
 func testHeader(t *testing.T, r *http.Request, header string, want string) {
 	t.Helper()
 	if got := r.Header.Get(header); got != want {
@@ -275,3 +288,188 @@ func TestActiveService_Delete(t *testing.T) {
 		t.Errorf("Account.Delete returned error: %v", err)
 	}
 }
+
+func TestAccountService_Update(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PATCH")
+		testHeader(t, r, "Content-Type", "application/json")
+
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("Request body is not valid JSON: %v", err)
+		}
+		want := map[string]interface{}{
+			"data": map[string]interface{}{
+				"id":      "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+				"type":    "accounts",
+				"version": float64(3),
+				"attributes": map[string]interface{}{
+					"customer_id":              "567",
+					"account_matching_opt_out": true,
+				},
+			},
+		}
+		if !reflect.DeepEqual(v, want) {
+			t.Errorf("Request body = %+v, want %+v", v, want)
+		}
+
+		_, e := fmt.Fprint(w, `{
+									  "data": {
+										"attributes": {
+										  "customer_id": "567",
+										  "account_matching_opt_out": true
+										},
+										"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+										"type": "accounts",
+										"version": 4
+									  },
+									  "links": {
+										"self": "/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+									  }
+									}`)
+		if e != nil {
+		}
+	})
+
+	account, links, _, err := client.Account.Update(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", 3,
+		&AccountUpdateRequestAttributes{
+			CustomerID:            String("567"),
+			AccountMatchingOptOut: Bool(true),
+		})
+	if err != nil {
+		t.Fatalf("Account.Update returned error: %v", err)
+	}
+	if account.Version != 4 {
+		t.Errorf("Account.Update returned version %v, want 4", account.Version)
+	}
+	if account.Attributes.CustomerID != "567" || !account.Attributes.AccountMatchingOptOut {
+		t.Errorf("Account.Update returned attributes %+v", account.Attributes)
+	}
+	if links.Self != "/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc" {
+		t.Errorf("Account.Update returned links %+v", links)
+	}
+}
+
+func TestAccountService_Update_VersionConflict(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PATCH")
+		w.WriteHeader(http.StatusConflict)
+		_, e := fmt.Fprint(w, `{"error_message": "invalid version"}`)
+		if e != nil {
+		}
+	})
+
+	_, _, resp, err := client.Account.Update(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", 1,
+		&AccountUpdateRequestAttributes{CustomerID: String("567")})
+	if !IsConflict(err) {
+		t.Errorf("Account.Update returned error %v, want a conflict", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusConflict {
+		t.Errorf("Account.Update returned response %v, want status 409", resp)
+	}
+}
+
+func TestAccountService_List_Filter(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		want := url.Values{
+			"page[number]":         {"1"},
+			"filter[bank_id_code]": {"GBDSC"},
+			"filter[country]":      {"GB,IE"},
+		}
+		if got := r.URL.Query(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Request query = %v, want %v", got, want)
+		}
+		_, e := fmt.Fprint(w, `{"data": []}`)
+		if e != nil {
+		}
+	})
+
+	opts := &AccountListOptions{
+		ListOptions: ListOptions{Page: 1},
+		AccountListFilter: AccountListFilter{
+			BankIDCode: []string{"GBDSC"},
+			Country:    []string{"GB", "IE"},
+		},
+	}
+	if _, _, _, err := client.Account.List(context.Background(), opts); err != nil {
+		t.Errorf("Account.List returned error: %v", err)
+	}
+}
+
+func TestAccountCreateRequestAttributes_Marshal(t *testing.T) {
+	b, err := json.Marshal(&AccountCreateRequestAttributes{
+		Country:          "GB",
+		Name:             []string{"Samantha Holder"},
+		AlternativeNames: []string{"Sam Holder"},
+		JointAccount:     Bool(false),
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	want := `{"alternative_names":["Sam Holder"],"country":"GB","joint_account":false,"name":["Samantha Holder"]}`
+	if string(b) != want {
+		t.Errorf("json.Marshal returned %s, want %s", b, want)
+	}
+}
+
+func TestAccountAttributes_Unmarshal(t *testing.T) {
+	data := `{
+				"country": "GB",
+				"name": ["Samantha Holder", "Second Line"],
+				"alternative_names": ["Sam Holder"],
+				"alternative_bank_account_names": ["Sam H"],
+				"status": "confirmed",
+				"status_reason": "unspecified",
+				"user_defined_data": [{"key": "Some account related key", "value": "Some account related value"}],
+				"validation_type": "card",
+				"reference_mask": "############",
+				"acceptance_qualifier": "same_day",
+				"processing_service": "ABC Bank",
+				"private_identification": {
+					"birth_date": "2017-07-23",
+					"birth_country": "GB",
+					"identification": "13YH458762",
+					"address": ["10 Avenue des Champs"],
+					"city": "London",
+					"country": "GB"
+				}
+			}`
+	var got AccountAttributes
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	want := AccountAttributes{
+		Country:                     "GB",
+		Name:                        []string{"Samantha Holder", "Second Line"},
+		AlternativeNames:            []string{"Sam Holder"},
+		AlternativeBankAccountNames: []string{"Sam H"},
+		Status:                      AccountStatusConfirmed,
+		StatusReason:                "unspecified",
+		UserDefinedData:             []*UserDefinedData{{Key: "Some account related key", Value: "Some account related value"}},
+		ValidationType:              AccountValidationTypeCard,
+		ReferenceMask:               "############",
+		AcceptanceQualifier:         "same_day",
+		ProcessingService:           "ABC Bank",
+		PrivateIdentification: &PrivateIdentification{
+			BirthDate:      "2017-07-23",
+			BirthCountry:   "GB",
+			Identification: "13YH458762",
+			Address:        []string{"10 Avenue des Champs"},
+			City:           "London",
+			Country:        "GB",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Unmarshal returned %+v, want %+v", got, want)
+	}
+}
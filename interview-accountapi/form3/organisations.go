@@ -0,0 +1,253 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OrganisationService handles the organisation units of the Form3 API.
+// Every resource of the API, e.g. an account, belongs to an organisation.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-units
+type OrganisationService service
+
+type OrganisationAttributes struct {
+	Name string `json:"name"`
+}
+
+type Organisation struct {
+	Attributes *OrganisationAttributes `json:"attributes"`
+	CreatedOn  time.Time               `json:"created_on"`
+	ID         string                  `json:"id"`
+	ModifiedOn time.Time               `json:"modified_on"`
+
+	// ID of the parent organisation, empty for a root organisation.
+	OrganisationID string `json:"organisation_id,omitempty"`
+
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+}
+
+// OrganisationLinks holds the links of an organisation response.
+type OrganisationLinks struct {
+	Self string `json:"self"`
+}
+
+type OrganisationResponse struct {
+	Data  *Organisation      `json:"data"`
+	Links *OrganisationLinks `json:"links"`
+}
+
+type OrganisationListResponse struct {
+	Data  []*Organisation `json:"data"`
+	Links *ListLinks      `json:"links"`
+}
+
+type OrganisationCreateRequestData struct {
+	Attributes     *OrganisationAttributes `json:"attributes"`
+	OrganisationID string                  `json:"organisation_id,omitempty"`
+	ID             string                  `json:"id"`
+	Type           string                  `json:"type"`
+}
+
+type OrganisationCreateRequest struct {
+	Data *OrganisationCreateRequestData `json:"data"`
+}
+
+// OrganisationUpdateRequestAttributes holds the attributes changed by an
+// update. Nil fields are left unchanged.
+type OrganisationUpdateRequestAttributes struct {
+	Name *string `json:"name,omitempty"`
+}
+
+type OrganisationUpdateRequestData struct {
+	Attributes *OrganisationUpdateRequestAttributes `json:"attributes"`
+	ID         string                               `json:"id"`
+	Type       string                               `json:"type"`
+	Version    int                                  `json:"version"`
+}
+
+type OrganisationUpdateRequest struct {
+	Data *OrganisationUpdateRequestData `json:"data"`
+}
+
+// OrganisationListOptions specifies the optional parameters to the
+// OrganisationService.List method.
+type OrganisationListOptions struct {
+	ListOptions
+}
+
+// Create creates an organisation unit with the given id under the parent
+// organisation parentID, or a root organisation if parentID is empty.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-units-create
+func (s *OrganisationService) Create(ctx context.Context, id string, parentID string, attributes *OrganisationAttributes) (*Organisation, *OrganisationLinks, *Response, error) {
+	ctx = withOperation(ctx, "Organisation.Create")
+
+	req, err := s.client.NewRequest("POST", "/v1/organisation/units",
+		&OrganisationCreateRequest{&OrganisationCreateRequestData{
+			attributes,
+			parentID,
+			id,
+			"organisations"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *OrganisationResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-units-fetch
+func (s *OrganisationService) Fetch(ctx context.Context, id string) (*Organisation, *OrganisationLinks, *Response, error) {
+	ctx = withOperation(ctx, "Organisation.Fetch")
+
+	u := fmt.Sprintf("/v1/organisation/units/%s", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *OrganisationResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-units-list
+func (s *OrganisationService) List(ctx context.Context, opts *OrganisationListOptions) ([]*Organisation, *ListLinks, *Response, error) {
+	ctx = withOperation(ctx, "Organisation.List")
+
+	u, err := addOptions("/v1/organisation/units", opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return s.list(ctx, u)
+}
+
+// list fetches the page of organisations at u.
+func (s *OrganisationService) list(ctx context.Context, u string) ([]*Organisation, *ListLinks, *Response, error) {
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *OrganisationListResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+	resp.populatePageValues(r.Links)
+
+	return r.Data, r.Links, resp, nil
+}
+
+// OrganisationIterator iterates over the organisations of a list, fetching
+// pages lazily as they are needed, like AccountIterator.
+type OrganisationIterator struct {
+	pager
+	page         []*Organisation
+	organisation *Organisation
+}
+
+// ListAll returns an iterator over all organisations, starting at the page
+// selected by opts, like AccountService.ListAll.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-units-list
+func (s *OrganisationService) ListAll(ctx context.Context, opts *OrganisationListOptions) *OrganisationIterator {
+	ctx = withOperation(ctx, "Organisation.ListAll")
+
+	it := &OrganisationIterator{}
+	it.pager = pager{ctx: ctx, load: func(u string) (int, string, string, string, error) {
+		var (
+			organisations []*Organisation
+			links         *ListLinks
+			err           error
+		)
+		if u == "" {
+			organisations, links, _, err = s.List(ctx, opts)
+		} else {
+			organisations, links, _, err = s.list(ctx, u)
+		}
+		if err != nil {
+			return 0, "", "", "", err
+		}
+		it.page = organisations
+		if links == nil {
+			return len(organisations), "", "", "", nil
+		}
+		return len(organisations), links.Next, links.Self, links.Last, nil
+	}}
+	return it
+}
+
+// Next advances the iterator to the next organisation. It returns false
+// when the iteration stops, either because there are no more organisations
+// or because of an error reported by Err.
+func (it *OrganisationIterator) Next() bool {
+	it.organisation = nil
+	if !it.advance() {
+		return false
+	}
+	it.organisation, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Organisation returns the current organisation. It is only valid after a
+// call to Next that returned true.
+func (it *OrganisationIterator) Organisation() *Organisation {
+	return it.organisation
+}
+
+// Update changes the attributes set in patch on the organisation with the
+// given id. version must be the current version of the organisation,
+// otherwise the update is rejected with a *ConflictError.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-units-patch
+func (s *OrganisationService) Update(ctx context.Context, id string, version int, patch *OrganisationUpdateRequestAttributes) (*Organisation, *OrganisationLinks, *Response, error) {
+	ctx = withOperation(ctx, "Organisation.Update")
+
+	u := fmt.Sprintf("/v1/organisation/units/%s", id)
+
+	req, err := s.client.NewRequest("PATCH", u,
+		&OrganisationUpdateRequest{&OrganisationUpdateRequestData{
+			patch,
+			id,
+			"organisations",
+			version}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *OrganisationResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-units-delete
+func (s *OrganisationService) Delete(ctx context.Context, id string, version int) (*Response, error) {
+	ctx = withOperation(ctx, "Organisation.Delete")
+
+	u := fmt.Sprintf("/v1/organisation/units/%s?version=%d", id, version)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
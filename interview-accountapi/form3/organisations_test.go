@@ -0,0 +1,154 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const organisationJSON = `{
+	"data":{
+		"attributes":{"name":"Acme Payments"},
+		"created_on":"2020-11-11T10:40:44.709Z",
+		"id":"ba61483c-d5c5-4f50-ae81-6b8c039bea43",
+		"modified_on":"2020-11-11T10:40:44.709Z",
+		"organisation_id":"743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb",
+		"type":"organisations",
+		"version":0
+	},
+	"links":{"self":"/v1/organisation/units/ba61483c-d5c5-4f50-ae81-6b8c039bea43"}
+}`
+
+func TestOrganisationService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/units", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Content-Type", "application/json")
+		v := new(OrganisationCreateRequest)
+		json.NewDecoder(r.Body).Decode(v)
+		want := &OrganisationCreateRequest{&OrganisationCreateRequestData{
+			&OrganisationAttributes{Name: "Acme Payments"},
+			"743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb",
+			"ba61483c-d5c5-4f50-ae81-6b8c039bea43",
+			"organisations",
+		}}
+		if !reflect.DeepEqual(v, want) {
+			t.Errorf("Request body = %+v, want %+v", v.Data, want.Data)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, organisationJSON)
+	})
+
+	org, links, _, err := client.Organisation.Create(context.Background(),
+		"ba61483c-d5c5-4f50-ae81-6b8c039bea43", "743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb",
+		&OrganisationAttributes{Name: "Acme Payments"})
+	if err != nil {
+		t.Fatalf("Organisation.Create returned error: %v", err)
+	}
+	if org.ID != "ba61483c-d5c5-4f50-ae81-6b8c039bea43" || org.Attributes.Name != "Acme Payments" ||
+		org.OrganisationID != "743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb" {
+		t.Errorf("Organisation.Create returned %+v", org)
+	}
+	if links.Self != "/v1/organisation/units/ba61483c-d5c5-4f50-ae81-6b8c039bea43" {
+		t.Errorf("Organisation.Create returned links %+v", links)
+	}
+}
+
+func TestOrganisationService_Fetch(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/units/ba61483c-d5c5-4f50-ae81-6b8c039bea43", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, organisationJSON)
+	})
+
+	org, _, _, err := client.Organisation.Fetch(context.Background(), "ba61483c-d5c5-4f50-ae81-6b8c039bea43")
+	if err != nil {
+		t.Fatalf("Organisation.Fetch returned error: %v", err)
+	}
+	if org.Attributes.Name != "Acme Payments" || org.Version != 0 {
+		t.Errorf("Organisation.Fetch returned %+v", org)
+	}
+
+	_, _, _, err = client.Organisation.Fetch(context.Background(), "00000000-0000-4000-8000-000000000000")
+	if !IsNotFound(err) {
+		t.Errorf("Organisation.Fetch of a missing organisation returned %v, want not found", err)
+	}
+}
+
+func TestOrganisationService_ListAll(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/units", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("page[number]") {
+		case "1":
+			fmt.Fprint(w, `{"data":[{"id":"2"}],"links":{"self":"/v1/organisation/units?page%5Bnumber%5D=1&page%5Bsize%5D=2"}}`)
+		default:
+			testFormValues(t, r, values{"page[size]": "2"})
+			fmt.Fprint(w, `{"data":[{"id":"0"},{"id":"1"}],"links":{
+				"self":"/v1/organisation/units?page%5Bnumber%5D=0&page%5Bsize%5D=2",
+				"next":"/v1/organisation/units?page%5Bnumber%5D=1&page%5Bsize%5D=2"}}`)
+		}
+	})
+
+	var ids []string
+	it := client.Organisation.ListAll(context.Background(), &OrganisationListOptions{ListOptions{PerPage: 2}})
+	for it.Next() {
+		ids = append(ids, it.Organisation().ID)
+	}
+	if it.Err() != nil {
+		t.Fatalf("ListAll returned error: %v", it.Err())
+	}
+	if want := []string{"0", "1", "2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListAll returned %v, want %v", ids, want)
+	}
+}
+
+func TestOrganisationService_Update(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/units/ba61483c-d5c5-4f50-ae81-6b8c039bea43", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PATCH")
+		v := new(OrganisationUpdateRequest)
+		json.NewDecoder(r.Body).Decode(v)
+		if v.Data.Version != 3 || *v.Data.Attributes.Name != "Acme" || v.Data.Type != "organisations" {
+			t.Errorf("Request body = %+v", v.Data)
+		}
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error_message":"invalid version"}`)
+	})
+
+	_, _, _, err := client.Organisation.Update(context.Background(), "ba61483c-d5c5-4f50-ae81-6b8c039bea43", 3,
+		&OrganisationUpdateRequestAttributes{Name: String("Acme")})
+	if !IsConflict(err) {
+		t.Errorf("Organisation.Update returned %v, want a conflict", err)
+	}
+}
+
+func TestOrganisationService_Delete(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/units/ba61483c-d5c5-4f50-ae81-6b8c039bea43", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testFormValues(t, r, values{"version": "0"})
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := client.Organisation.Delete(context.Background(), "ba61483c-d5c5-4f50-ae81-6b8c039bea43", 0)
+	if err != nil {
+		t.Fatalf("Organisation.Delete returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Organisation.Delete returned status %v, want 204", resp.StatusCode)
+	}
+}
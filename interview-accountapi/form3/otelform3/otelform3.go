@@ -0,0 +1,220 @@
+// Package otelform3 traces the calls of a form3.Client with OpenTelemetry.
+//
+// It is a separate module, so that the form3 module does not depend on
+// OpenTelemetry. Tracing is enabled by adding its middleware to a client:
+//
+//	client, _ := form3.NewClient(form3.WithMiddleware(otelform3.Middleware()))
+//
+// Every HTTP call of the client, i.e. every call of Client.Do, gets a client
+// span named after the service operation making it, e.g. Account.Create,
+// carrying the HTTP method, the route template, the status code, the number
+// of retries and, for accounts, the account ID. Operations making several
+// calls, e.g. AccountService.CreateOrGet and the ListAll methods, get several
+// sibling spans of the same name. The W3C trace context of the span is sent
+// with the request, and errors reported by form3.CheckResponse are recorded
+// on the span.
+package otelform3
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/vslovik/form3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer of the spans.
+const TracerName = "github.com/vslovik/form3/otelform3"
+
+// Span attributes specific to the Form3 API.
+const (
+	OperationKey = attribute.Key("form3.operation")
+	AccountIDKey = attribute.Key("form3.account.id")
+)
+
+// maxErrorBodySize is the largest error response body read to record the
+// error on the span.
+const maxErrorBodySize = 64 << 10
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// Option configures the middleware returned by Middleware.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider of the spans. It defaults to
+// the global provider, otel.GetTracerProvider().
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagator sets the propagator injecting the trace context into the
+// requests. It defaults to the W3C trace context, propagation.TraceContext.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Middleware returns a form3.Middleware creating a span for every HTTP call
+// of a form3.Client.
+func Middleware(opts ...Option) form3.Middleware {
+	c := &config{propagator: propagation.TraceContext{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.provider == nil {
+		c.provider = otel.GetTracerProvider()
+	}
+	tracer := c.provider.Tracer(TracerName)
+
+	return func(next form3.RoundTripFunc) form3.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			operation := form3.Operation(req.Context())
+			name := operation
+			if name == "" {
+				name = "form3 " + req.Method
+			}
+			route, ids := Route(req.URL.Path)
+
+			attrs := []attribute.KeyValue{
+				attribute.String("http.request.method", req.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.full", fullURL(req.URL)),
+				attribute.String("server.address", req.URL.Hostname()),
+			}
+			if operation != "" {
+				attrs = append(attrs, OperationKey.String(operation))
+			}
+			if strings.HasPrefix(operation, "Account.") {
+				if id := accountID(req, ids); id != "" {
+					attrs = append(attrs, AccountIDKey.String(id))
+				}
+			}
+			ctx, span := tracer.Start(req.Context(), name,
+				trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			defer span.End()
+
+			attempts := 0
+			ctx = form3.WithClientTrace(ctx, &form3.ClientTrace{Attempt: func(n int) {
+				attempts = n
+			}})
+			req = req.WithContext(ctx)
+			c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(req)
+			if attempts > 1 {
+				span.SetAttributes(attribute.Int("http.request.resend_count", attempts-1))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+			if apiErr := checkResponse(resp); apiErr != nil {
+				span.RecordError(apiErr)
+				span.SetStatus(codes.Error, apiErr.Error())
+			}
+			return resp, nil
+		}
+	}
+}
+
+// checkResponse returns the error of resp reported by form3.CheckResponse,
+// leaving the body of resp unread for the client.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return err
+	}
+
+	copied := *resp
+	copied.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return form3.CheckResponse(&copied)
+}
+
+// Route returns the route template of the path of a Form3 API URL, e.g.
+// "/v1/organisation/accounts/{id}" for
+// "/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", and the
+// IDs replaced by placeholders. API paths alternate between collections and
+// IDs after the version and the API group. The ID following the first
+// collection is named {id}, the others after their collection, e.g.
+// "/v1/transaction/payments/{id}/submissions/{submission_id}".
+func Route(path string) (string, []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	// A base URL with a path prefix puts segments before the version.
+	start := 0
+	for i, s := range segments {
+		if len(s) > 1 && s[0] == 'v' && strings.Trim(s[1:], "0123456789") == "" {
+			start = i
+			break
+		}
+	}
+	var ids []string
+	for i := start + 3; i < len(segments); i += 2 {
+		ids = append(ids, segments[i])
+		if i == start+3 {
+			segments[i] = "{id}"
+		} else {
+			segments[i] = "{" + strings.TrimSuffix(segments[i-1], "s") + "_id}"
+		}
+	}
+	return "/" + strings.Join(segments[start:], "/"), ids
+}
+
+// fullURL returns u without its query and user info. List queries carry
+// filters such as filter[iban] and filter[customer_id], which must not end up
+// in traces.
+func fullURL(u *url.URL) string {
+	copied := *u
+	copied.User = nil
+	copied.RawQuery = ""
+	copied.ForceQuery = false
+	return copied.String()
+}
+
+// accountID returns the ID of the account of an account request: the ID in
+// its path, or else the ID in its body.
+func accountID(req *http.Request, ids []string) string {
+	if len(ids) > 0 {
+		return ids[0]
+	}
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	var v struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if json.NewDecoder(body).Decode(&v) != nil {
+		return ""
+	}
+	return v.Data.ID
+}
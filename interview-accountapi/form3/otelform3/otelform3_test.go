@@ -0,0 +1,214 @@
+package otelform3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vslovik/form3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	testAccountID      = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	testOrganisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+)
+
+// setup returns a traced client of a test server served by mux, and the
+// exporter of its spans.
+func setup(t *testing.T, opts ...form3.ClientOption) (*form3.Client, *http.ServeMux, *tracetest.InMemoryExporter, func()) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	opts = append([]form3.ClientOption{
+		form3.WithBaseURL(server.URL),
+		form3.WithMiddleware(Middleware(WithTracerProvider(provider))),
+	}, opts...)
+	client, err := form3.NewClient(opts...)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	return client, mux, exporter, server.Close
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestMiddleware_Create(t *testing.T) {
+	client, mux, exporter, teardown := setup(t)
+	defer teardown()
+
+	var traceparent string
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"data":{"id":"%s"}}`, testAccountID)
+	})
+
+	_, _, _, err := client.Account.Create(context.Background(), testAccountID, testOrganisationID,
+		&form3.AccountCreateRequestAttributes{Country: "GB"})
+	if err != nil {
+		t.Fatalf("Account.Create returned error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Exported %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "Account.Create" || span.Status.Code == codes.Error {
+		t.Errorf("Span %q has status %+v", span.Name, span.Status)
+	}
+	attrs := attributes(span)
+	want := map[attribute.Key]string{
+		"http.request.method": "POST",
+		"http.route":          "/v1/organisation/accounts",
+		"form3.operation":     "Account.Create",
+		"form3.account.id":    testAccountID,
+	}
+	for k, v := range want {
+		if attrs[k].AsString() != v {
+			t.Errorf("Attribute %v = %q, want %q", k, attrs[k].AsString(), v)
+		}
+	}
+	if attrs["http.response.status_code"].AsInt64() != http.StatusCreated {
+		t.Errorf("Attribute http.response.status_code = %v, want 201", attrs["http.response.status_code"].AsInt64())
+	}
+	if _, ok := attrs["http.request.resend_count"]; ok {
+		t.Errorf("Attribute http.request.resend_count set without retries")
+	}
+
+	wantParent := fmt.Sprintf("00-%s-%s-01", span.SpanContext.TraceID(), span.SpanContext.SpanID())
+	if traceparent != wantParent {
+		t.Errorf("Traceparent = %q, want %q", traceparent, wantParent)
+	}
+}
+
+func TestMiddleware_Error(t *testing.T) {
+	client, mux, exporter, teardown := setup(t, form3.WithRetryPolicy(form3.RetryPolicy{
+		MaxAttempts:          3,
+		BaseBackoff:          time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}))
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/v1/organisation/accounts/"+testAccountID, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"error_message":"record %s does not exist"}`, testAccountID)
+	})
+
+	_, _, _, err := client.Account.Fetch(context.Background(), testAccountID)
+	if !form3.IsNotFound(err) || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("Account.Fetch returned %v, want the not found error of the API", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Exported %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	attrs := attributes(span)
+	if attrs["http.route"].AsString() != "/v1/organisation/accounts/{id}" || attrs["form3.account.id"].AsString() != testAccountID {
+		t.Errorf("Span attributes = %v", span.Attributes)
+	}
+	if attrs["http.request.resend_count"].AsInt64() != 2 {
+		t.Errorf("Attribute http.request.resend_count = %v, want 2", attrs["http.request.resend_count"].AsInt64())
+	}
+	if span.Status.Code != codes.Error || !strings.Contains(span.Status.Description, "does not exist") {
+		t.Errorf("Span status = %+v", span.Status)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+		t.Errorf("Span events = %+v, want the recorded error", span.Events)
+	}
+}
+
+func TestMiddleware_TransportError(t *testing.T) {
+	client, _, exporter, teardown := setup(t)
+	teardown()
+
+	_, err := client.Organisation.Delete(context.Background(), testOrganisationID, 0)
+	var urlErr interface{ Timeout() bool }
+	if err == nil || !errors.As(err, &urlErr) {
+		t.Fatalf("Organisation.Delete of a closed server returned %v", err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "Organisation.Delete" || spans[0].Status.Code != codes.Error {
+		t.Errorf("Exported spans = %+v", spans)
+	}
+	if _, ok := attributes(spans[0])["form3.account.id"]; ok {
+		t.Errorf("Organisation span has an account ID")
+	}
+}
+
+func TestMiddleware_QueryNotRecorded(t *testing.T) {
+	client, mux, exporter, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	opts := &form3.AccountListOptions{AccountListFilter: form3.AccountListFilter{
+		Iban:       []string{"GB33BUKB20201555555555"},
+		CustomerID: []string{"c"},
+	}}
+	if _, _, _, err := client.Account.List(context.Background(), opts); err != nil {
+		t.Fatalf("Account.List returned error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Exported %d spans, want 1", len(spans))
+	}
+	for _, kv := range spans[0].Attributes {
+		if v := kv.Value.Emit(); strings.Contains(v, "GB33BUKB20201555555555") || strings.Contains(v, "filter") {
+			t.Errorf("Attribute %v = %q records the query", kv.Key, v)
+		}
+	}
+	if got := attributes(spans[0])["url.full"].AsString(); strings.Contains(got, "?") {
+		t.Errorf("Attribute url.full = %q, want no query", got)
+	}
+}
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		path  string
+		route string
+		ids   []string
+	}{
+		{"/v1/organisation/accounts", "/v1/organisation/accounts", nil},
+		{"/v1/organisation/accounts/" + testAccountID, "/v1/organisation/accounts/{id}", []string{testAccountID}},
+		{"/prefix/v1/organisation/units/o", "/v1/organisation/units/{id}", []string{"o"}},
+		{"/v1/transaction/payments/p/submissions/s", "/v1/transaction/payments/{id}/submissions/{submission_id}", []string{"p", "s"}},
+		{"/v1/transaction/payments/p/returns/r/admissions", "/v1/transaction/payments/{id}/returns/{return_id}/admissions", []string{"p", "r"}},
+	}
+	for _, tt := range tests {
+		route, ids := Route(tt.path)
+		if route != tt.route || !reflect.DeepEqual(ids, tt.ids) {
+			t.Errorf("Route(%q) = %q, %q, want %q, %q", tt.path, route, ids, tt.route, tt.ids)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package form3
+
+import "context"
+
+// AccountIterator iterates over the accounts of a list, fetching pages
+// lazily as they are needed. Use it as:
+//
+//	opts := &form3.AccountListOptions{ListOptions: form3.ListOptions{PerPage: 100}}
+//	it := client.Account.ListAll(ctx, opts)
+//	for it.Next() {
+//		account := it.Account()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type AccountIterator struct {
+	pager
+	page    []*Account
+	account *Account
+}
+
+// ListAll returns an iterator over all accounts, starting at the page
+// selected by opts. The following pages are reached through the next links
+// of the responses, so the iteration stops after the page linked as last or
+// without a next link, on the first empty page, on the first error or when
+// ctx is done.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#organisation-accounts-list
+func (s *AccountService) ListAll(ctx context.Context, opts *AccountListOptions) *AccountIterator {
+	ctx = withOperation(ctx, "Account.ListAll")
+
+	it := &AccountIterator{}
+	it.pager = pager{ctx: ctx, load: func(u string) (int, string, string, string, error) {
+		var (
+			accounts []*Account
+			links    *AccountListLinks
+			err      error
+		)
+		if u == "" {
+			accounts, links, _, err = s.List(ctx, opts)
+		} else {
+			accounts, links, _, err = s.list(ctx, u)
+		}
+		if err != nil {
+			return 0, "", "", "", err
+		}
+		it.page = accounts
+		if links == nil {
+			return len(accounts), "", "", "", nil
+		}
+		return len(accounts), links.Next, links.Self, links.Last, nil
+	}}
+	return it
+}
+
+// Next advances the iterator to the next account, fetching the next page if
+// needed. It returns false when the iteration stops, either because there
+// are no more accounts or because of an error reported by Err.
+func (it *AccountIterator) Next() bool {
+	it.account = nil
+	if !it.advance() {
+		return false
+	}
+	it.account, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Account returns the current account. It is only valid after a call to
+// Next that returned true.
+func (it *AccountIterator) Account() *Account {
+	return it.account
+}
+
+// ListLinks holds the pagination links of a list response.
+type ListLinks struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Self  string `json:"self"`
+}
+
+// pager holds the pagination state shared by the iterators of the services.
+// load fetches the page at URL u, or the first page if u is empty, stores
+// its items in the iterator and returns their number with the next, self and
+// last links of the page.
+type pager struct {
+	ctx  context.Context
+	load func(u string) (n int, next, self, last string, err error)
+
+	n       int    // items left in the current page
+	next    string // URL of the next page, empty when there is none
+	started bool
+	err     error
+}
+
+// advance reports whether an item is left in the current page, fetching the
+// next pages as needed, and consumes it. The iterator pops the item from its
+// page when it returns true.
+func (p *pager) advance() bool {
+	if p.err != nil {
+		return false
+	}
+	if p.ctx != nil && p.ctx.Err() != nil {
+		p.err = p.ctx.Err()
+		return false
+	}
+
+	for p.n == 0 {
+		if p.started && p.next == "" {
+			return false
+		}
+		if !p.fetch() {
+			return false
+		}
+	}
+	p.n--
+	return true
+}
+
+// fetch loads the next page, reporting whether it holds items.
+func (p *pager) fetch() bool {
+	u := ""
+	if p.started {
+		u = p.next
+	}
+	p.started = true
+
+	n, next, self, last, err := p.load(u)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	previous := p.next
+	p.n = n
+	p.next = ""
+	// The page linked as last needs no further request, even if the API
+	// links a next page from it.
+	if next != "" && next != self && next != previous && (last == "" || self != last) && n > 0 {
+		p.next = next
+	}
+	return n > 0
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *pager) Err() error {
+	return p.err
+}
@@ -0,0 +1,162 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// servePages serves accounts "0" to "n-1" in pages of size accounts with
+// JSON:API pagination links, counting the requests in *calls.
+func servePages(mux *http.ServeMux, n, size int, calls *int) {
+	serveLinkedPages(mux, n, size, calls, false)
+}
+
+// serveLinkedPages is servePages, also linking an empty next page from the
+// last page if nextOfLast is set.
+func serveLinkedPages(mux *http.ServeMux, n, size int, calls *int, nextOfLast bool) {
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		page := 0
+		fmt.Sscan(r.URL.Query().Get("page[number]"), &page)
+		last := (n - 1) / size
+
+		link := func(p int) string {
+			return fmt.Sprintf(`"/v1/organisation/accounts?page%%5Bnumber%%5D=%d&page%%5Bsize%%5D=%d"`, p, size)
+		}
+		data := ""
+		for i := page * size; i < n && i < (page+1)*size; i++ {
+			if data != "" {
+				data += ","
+			}
+			data += fmt.Sprintf(`{"id":"%d","type":"accounts"}`, i)
+		}
+		next := ""
+		if page < last || nextOfLast {
+			next = `,"next":` + link(page+1)
+		}
+		fmt.Fprintf(w, `{"data":[%s],"links":{"first":%s,"last":%s,"self":%s%s}}`,
+			data, link(0), link(last), link(page), next)
+	})
+}
+
+func TestAccountService_ListAll(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	servePages(mux, 5, 2, &calls)
+
+	var ids []string
+	it := client.Account.ListAll(context.Background(), &AccountListOptions{ListOptions: ListOptions{PerPage: 2}})
+	for it.Next() {
+		ids = append(ids, it.Account().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("ListAll returned error: %v", err)
+	}
+	if want := []string{"0", "1", "2", "3", "4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListAll returned %v, want %v", ids, want)
+	}
+	if calls != 3 {
+		t.Errorf("ListAll fetched %d pages, want 3", calls)
+	}
+	if it.Next() {
+		t.Errorf("Next after the end returned true")
+	}
+}
+
+func TestAccountService_ListAll_StopsAtLast(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	serveLinkedPages(mux, 5, 2, &calls, true)
+
+	n := 0
+	it := client.Account.ListAll(context.Background(), &AccountListOptions{ListOptions: ListOptions{PerPage: 2}})
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("ListAll returned error: %v", err)
+	}
+	if n != 5 || calls != 3 {
+		t.Errorf("ListAll returned %d accounts in %d pages, want 5 in 3", n, calls)
+	}
+}
+
+func TestAccountService_ListAll_Lazy(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	servePages(mux, 5, 2, &calls)
+
+	it := client.Account.ListAll(context.Background(), &AccountListOptions{ListOptions: ListOptions{PerPage: 2}})
+	if calls != 0 {
+		t.Errorf("ListAll fetched %d pages before Next, want 0", calls)
+	}
+	it.Next()
+	it.Next()
+	if calls != 1 {
+		t.Errorf("ListAll fetched %d pages for 2 accounts, want 1", calls)
+	}
+}
+
+func TestAccountService_ListAll_Empty(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[],"links":{"self":"/v1/organisation/accounts"}}`)
+	})
+
+	it := client.Account.ListAll(context.Background(), nil)
+	if it.Next() {
+		t.Errorf("Next on an empty list returned true")
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("ListAll returned error: %v", err)
+	}
+}
+
+func TestAccountService_ListAll_Error(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	it := client.Account.ListAll(context.Background(), nil)
+	if it.Next() {
+		t.Errorf("Next returned true on error")
+	}
+	if !IsServerError(it.Err()) {
+		t.Errorf("ListAll returned error %v, want a server error", it.Err())
+	}
+}
+
+func TestAccountService_ListAll_ContextCanceled(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	servePages(mux, 5, 2, &calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := client.Account.ListAll(ctx, &AccountListOptions{ListOptions: ListOptions{PerPage: 2}})
+	if !it.Next() {
+		t.Fatalf("Next returned false, error: %v", it.Err())
+	}
+	cancel()
+	if it.Next() {
+		t.Errorf("Next after cancel returned true")
+	}
+	if it.Err() != context.Canceled {
+		t.Errorf("ListAll returned error %v, want %v", it.Err(), context.Canceled)
+	}
+}
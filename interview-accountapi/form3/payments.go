@@ -0,0 +1,396 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PaymentService handles the payments of the Form3 API and their
+// submissions to the payment schemes.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments
+type PaymentService service
+
+// Payment schemes.
+const (
+	PaymentSchemeFPS   = "FPS"
+	PaymentSchemeBacs  = "Bacs"
+	PaymentSchemeSEPA  = "SEPACT"
+	PaymentSchemeCHAPS = "CHAPS"
+)
+
+// Payment types.
+const (
+	PaymentTypeCredit = "Credit"
+	PaymentTypeDebit  = "Debit"
+)
+
+// Payment submission statuses. A submission ends as delivery_confirmed or
+// delivery_failed.
+const (
+	PaymentSubmissionStatusAccepted          = "accepted"
+	PaymentSubmissionStatusValidationPending = "validation_pending"
+	PaymentSubmissionStatusReleasedToGateway = "released_to_gateway"
+	PaymentSubmissionStatusQueuedForDelivery = "queued_for_delivery"
+	PaymentSubmissionStatusSubmitted         = "submitted"
+	PaymentSubmissionStatusDeliveryConfirmed = "delivery_confirmed"
+	PaymentSubmissionStatusDeliveryFailed    = "delivery_failed"
+)
+
+// PaymentParty is the debtor or the beneficiary of a payment.
+type PaymentParty struct {
+	AccountName       string   `json:"account_name,omitempty"`
+	AccountNumber     string   `json:"account_number,omitempty"`
+	AccountNumberCode string   `json:"account_number_code,omitempty"` // BBAN or IBAN
+	AccountType       int      `json:"account_type,omitempty"`
+	Address           []string `json:"address,omitempty"`
+	BankID            string   `json:"bank_id,omitempty"`
+	BankIDCode        string   `json:"bank_id_code,omitempty"`
+	Country           string   `json:"country,omitempty"`
+	Name              string   `json:"name,omitempty"`
+}
+
+// PaymentAttributes holds the attributes of a payment. Amount is a decimal
+// string, e.g. "100.21", so that it is not rounded by a float.
+type PaymentAttributes struct {
+	Amount               string        `json:"amount"`
+	BeneficiaryParty     *PaymentParty `json:"beneficiary_party,omitempty"`
+	Currency             string        `json:"currency"`
+	DebtorParty          *PaymentParty `json:"debtor_party,omitempty"`
+	EndToEndReference    string        `json:"end_to_end_reference,omitempty"`
+	NumericReference     string        `json:"numeric_reference,omitempty"`
+	PaymentPurpose       string        `json:"payment_purpose,omitempty"`
+	PaymentScheme        string        `json:"payment_scheme,omitempty"`
+	PaymentType          string        `json:"payment_type,omitempty"`
+	ProcessingDate       string        `json:"processing_date,omitempty"` // formatted as YYYY-MM-DD
+	Reference            string        `json:"reference,omitempty"`
+	SchemePaymentSubType string        `json:"scheme_payment_sub_type,omitempty"`
+	SchemePaymentType    string        `json:"scheme_payment_type,omitempty"`
+}
+
+type Payment struct {
+	Attributes     *PaymentAttributes `json:"attributes"`
+	CreatedOn      time.Time          `json:"created_on"`
+	ID             string             `json:"id"`
+	ModifiedOn     time.Time          `json:"modified_on"`
+	OrganisationID string             `json:"organisation_id"`
+	Type           string             `json:"type"`
+	Version        int                `json:"version"`
+}
+
+// PaymentLinks holds the links of a payment or payment submission response.
+type PaymentLinks struct {
+	Self string `json:"self"`
+}
+
+type PaymentResponse struct {
+	Data  *Payment      `json:"data"`
+	Links *PaymentLinks `json:"links"`
+}
+
+type PaymentListResponse struct {
+	Data  []*Payment `json:"data"`
+	Links *ListLinks `json:"links"`
+}
+
+type PaymentCreateRequestData struct {
+	Attributes     *PaymentAttributes `json:"attributes"`
+	OrganisationID string             `json:"organisation_id"`
+	ID             string             `json:"id"`
+	Type           string             `json:"type"`
+}
+
+type PaymentCreateRequest struct {
+	Data *PaymentCreateRequestData `json:"data"`
+}
+
+// PaymentListOptions specifies the optional parameters to the
+// PaymentService.List method.
+type PaymentListOptions struct {
+	ListOptions
+}
+
+type PaymentSubmissionAttributes struct {
+	SchemeStatusCode   string     `json:"scheme_status_code,omitempty"`
+	Status             string     `json:"status,omitempty"`
+	StatusReason       string     `json:"status_reason,omitempty"`
+	SubmissionDatetime *time.Time `json:"submission_datetime,omitempty"`
+}
+
+type PaymentSubmission struct {
+	Attributes     *PaymentSubmissionAttributes `json:"attributes"`
+	CreatedOn      time.Time                    `json:"created_on"`
+	ID             string                       `json:"id"`
+	ModifiedOn     time.Time                    `json:"modified_on"`
+	OrganisationID string                       `json:"organisation_id"`
+	Type           string                       `json:"type"`
+	Version        int                          `json:"version"`
+}
+
+// Final reports whether the submission reached a final status, delivered or
+// failed.
+func (s *PaymentSubmission) Final() bool {
+	if s.Attributes == nil {
+		return false
+	}
+	switch s.Attributes.Status {
+	case PaymentSubmissionStatusDeliveryConfirmed, PaymentSubmissionStatusDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+type PaymentSubmissionResponse struct {
+	Data  *PaymentSubmission `json:"data"`
+	Links *PaymentLinks      `json:"links"`
+}
+
+type PaymentSubmissionCreateRequestData struct {
+	OrganisationID string `json:"organisation_id"`
+	ID             string `json:"id"`
+	Type           string `json:"type"`
+}
+
+type PaymentSubmissionCreateRequest struct {
+	Data *PaymentSubmissionCreateRequestData `json:"data"`
+}
+
+// DefaultSubmissionPollInterval is the interval between two fetches of a
+// submission by WaitSubmission when none is given.
+const DefaultSubmissionPollInterval = time.Second
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-create
+func (s *PaymentService) Create(ctx context.Context, id string, organizationId string, attributes *PaymentAttributes) (*Payment, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Payment.Create")
+
+	req, err := s.client.NewRequest("POST", "/v1/transaction/payments",
+		&PaymentCreateRequest{&PaymentCreateRequestData{
+			attributes,
+			organizationId,
+			id,
+			"payments"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-fetch
+func (s *PaymentService) Fetch(ctx context.Context, id string) (*Payment, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Payment.Fetch")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-list
+func (s *PaymentService) List(ctx context.Context, opts *PaymentListOptions) ([]*Payment, *ListLinks, *Response, error) {
+	ctx = withOperation(ctx, "Payment.List")
+
+	u, err := addOptions("/v1/transaction/payments", opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return s.list(ctx, u)
+}
+
+// list fetches the page of payments at u.
+func (s *PaymentService) list(ctx context.Context, u string) ([]*Payment, *ListLinks, *Response, error) {
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentListResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+	resp.populatePageValues(r.Links)
+
+	return r.Data, r.Links, resp, nil
+}
+
+// PaymentIterator iterates over the payments of a list, fetching pages
+// lazily as they are needed, like AccountIterator.
+type PaymentIterator struct {
+	pager
+	page    []*Payment
+	payment *Payment
+}
+
+// ListAll returns an iterator over all payments, starting at the page
+// selected by opts, like AccountService.ListAll.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-list
+func (s *PaymentService) ListAll(ctx context.Context, opts *PaymentListOptions) *PaymentIterator {
+	ctx = withOperation(ctx, "Payment.ListAll")
+
+	it := &PaymentIterator{}
+	it.pager = pager{ctx: ctx, load: func(u string) (int, string, string, string, error) {
+		var (
+			payments []*Payment
+			links    *ListLinks
+			err      error
+		)
+		if u == "" {
+			payments, links, _, err = s.List(ctx, opts)
+		} else {
+			payments, links, _, err = s.list(ctx, u)
+		}
+		if err != nil {
+			return 0, "", "", "", err
+		}
+		it.page = payments
+		if links == nil {
+			return len(payments), "", "", "", nil
+		}
+		return len(payments), links.Next, links.Self, links.Last, nil
+	}}
+	return it
+}
+
+// Next advances the iterator to the next payment. It returns false when the
+// iteration stops, either because there are no more payments or because of
+// an error reported by Err.
+func (it *PaymentIterator) Next() bool {
+	it.payment = nil
+	if !it.advance() {
+		return false
+	}
+	it.payment, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Payment returns the current payment. It is only valid after a call to
+// Next that returned true.
+func (it *PaymentIterator) Payment() *Payment {
+	return it.payment
+}
+
+// CreateSubmission submits the payment with the given id to its payment
+// scheme. The submission is processed asynchronously; its status is followed
+// with FetchSubmission or WaitSubmission.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-create-payment-submission
+func (s *PaymentService) CreateSubmission(ctx context.Context, paymentID string, submissionID string, organizationId string) (*PaymentSubmission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Payment.CreateSubmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/submissions", paymentID)
+
+	req, err := s.client.NewRequest("POST", u,
+		&PaymentSubmissionCreateRequest{&PaymentSubmissionCreateRequestData{
+			organizationId,
+			submissionID,
+			"payment_submissions"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentSubmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-fetch-payment-submission
+func (s *PaymentService) FetchSubmission(ctx context.Context, paymentID string, submissionID string) (*PaymentSubmission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Payment.FetchSubmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/submissions/%s", paymentID, submissionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentSubmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// FetchAdmission fetches the admission of an inbound payment.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-fetch-payment-admission
+func (s *PaymentService) FetchAdmission(ctx context.Context, paymentID string, admissionID string) (*Admission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Payment.FetchAdmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/admissions/%s", paymentID, admissionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *AdmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// WaitSubmission fetches the submission every interval, or every
+// DefaultSubmissionPollInterval if interval is not positive, until it
+// reaches a final status, and returns it. When ctx is done, the last fetched
+// submission is returned with ctx.Err(), so callers should give ctx a
+// deadline. If ctx is done before the first fetch completes, no submission
+// has been fetched and nil is returned with ctx.Err().
+func (s *PaymentService) WaitSubmission(ctx context.Context, paymentID string, submissionID string, interval time.Duration) (*PaymentSubmission, *Response, error) {
+	ctx = withOperation(ctx, "Payment.WaitSubmission")
+
+	if interval <= 0 {
+		interval = DefaultSubmissionPollInterval
+	}
+	var last *PaymentSubmission
+	for {
+		submission, _, resp, err := s.FetchSubmission(ctx, paymentID, submissionID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, resp, ctx.Err()
+			}
+			return nil, resp, err
+		}
+		if submission.Final() {
+			return submission, resp, nil
+		}
+		last = submission
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return submission, resp, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
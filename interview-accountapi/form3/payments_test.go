@@ -0,0 +1,211 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testPaymentAttributes() *PaymentAttributes {
+	return &PaymentAttributes{
+		Amount:   "100.21",
+		Currency: "GBP",
+		BeneficiaryParty: &PaymentParty{
+			AccountName:       "W Owens",
+			AccountNumber:     "31926819",
+			AccountNumberCode: "BBAN",
+			BankID:            "403000",
+			BankIDCode:        "GBDSC",
+			Name:              "Wilfred Jeremiah Owens",
+		},
+		DebtorParty: &PaymentParty{
+			AccountNumber:     "GB29XABC10161234567801",
+			AccountNumberCode: "IBAN",
+			BankID:            "203301",
+			BankIDCode:        "GBDSC",
+			Name:              "Emelia Jane Brown",
+		},
+		EndToEndReference: "Wil piano Jan",
+		PaymentScheme:     PaymentSchemeFPS,
+		PaymentType:       PaymentTypeCredit,
+		ProcessingDate:    "2020-11-11",
+		Reference:         "Payment for Em's piano lessons",
+	}
+}
+
+func TestPaymentService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var raw map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&raw)
+		attributes, _ := raw["data"]["attributes"].(map[string]interface{})
+		if attributes["amount"] != "100.21" || raw["data"]["type"] != "payments" {
+			t.Errorf("Request body = %v", raw)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43","organisation_id":"743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb",
+			"type":"payments","version":0,"attributes":{"amount":"100.21","currency":"GBP","payment_scheme":"FPS",
+			"beneficiary_party":{"account_number":"31926819","bank_id":"403000"}}},
+			"links":{"self":"/v1/transaction/payments/4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43"}}`)
+	})
+
+	payment, links, _, err := client.Payment.Create(context.Background(),
+		"4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43", "743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb", testPaymentAttributes())
+	if err != nil {
+		t.Fatalf("Payment.Create returned error: %v", err)
+	}
+	if payment.Attributes.Amount != "100.21" || payment.Attributes.BeneficiaryParty.AccountNumber != "31926819" {
+		t.Errorf("Payment.Create returned %+v", payment.Attributes)
+	}
+	if links.Self != "/v1/transaction/payments/4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43" {
+		t.Errorf("Payment.Create returned links %+v", links)
+	}
+}
+
+func TestPaymentAttributes_Marshal(t *testing.T) {
+	a := testPaymentAttributes()
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	got := new(PaymentAttributes)
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, a) {
+		t.Errorf("Round trip of %s returned %+v", b, got)
+	}
+}
+
+func TestPaymentService_FetchList(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id":"4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43","attributes":{"amount":"1.00"}}}`)
+	})
+	mux.HandleFunc("/v1/transaction/payments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"page[number]": "1", "page[size]": "1"})
+		fmt.Fprint(w, `{"data":[{"id":"4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43"}],"links":{"self":"x"}}`)
+	})
+
+	payment, _, _, err := client.Payment.Fetch(context.Background(), "4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43")
+	if err != nil {
+		t.Fatalf("Payment.Fetch returned error: %v", err)
+	}
+	if payment.Attributes.Amount != "1.00" {
+		t.Errorf("Payment.Fetch returned %+v", payment)
+	}
+
+	it := client.Payment.ListAll(context.Background(), &PaymentListOptions{ListOptions{Page: 1, PerPage: 1}})
+	var n int
+	for it.Next() {
+		n++
+		if it.Payment().ID != "4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43" {
+			t.Errorf("Payment.ListAll returned %+v", it.Payment())
+		}
+	}
+	if it.Err() != nil || n != 1 {
+		t.Errorf("Payment.ListAll returned %d payments, error %v", n, it.Err())
+	}
+}
+
+func TestPaymentService_SubmitAndWait(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	const (
+		paymentID    = "4ee3a8d8-ca7b-4290-a52c-dd5b6165ec43"
+		submissionID = "7a67ba54-4c1a-4a9e-8b1c-7b86d5a4d4ca"
+	)
+	mux.HandleFunc("/v1/transaction/payments/"+paymentID+"/submissions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(PaymentSubmissionCreateRequest)
+		json.NewDecoder(r.Body).Decode(v)
+		if v.Data.ID != submissionID || v.Data.Type != "payment_submissions" {
+			t.Errorf("Request body = %+v", v.Data)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"data":{"id":"%s","attributes":{"status":"accepted"}}}`, submissionID)
+	})
+	statuses := []string{"accepted", "queued_for_delivery", "delivery_confirmed"}
+	fetches := 0
+	mux.HandleFunc("/v1/transaction/payments/"+paymentID+"/submissions/"+submissionID, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"data":{"id":"%s","attributes":{"status":"%s"}}}`, submissionID, statuses[fetches])
+		fetches++
+	})
+
+	ctx := context.Background()
+	submission, _, _, err := client.Payment.CreateSubmission(ctx, paymentID, submissionID, "743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb")
+	if err != nil {
+		t.Fatalf("Payment.CreateSubmission returned error: %v", err)
+	}
+	if submission.Final() {
+		t.Errorf("Accepted submission is final")
+	}
+
+	submission, _, err = client.Payment.WaitSubmission(ctx, paymentID, submissionID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Payment.WaitSubmission returned error: %v", err)
+	}
+	if submission.Attributes.Status != PaymentSubmissionStatusDeliveryConfirmed || fetches != 3 {
+		t.Errorf("Payment.WaitSubmission returned %+v after %d fetches", submission.Attributes, fetches)
+	}
+}
+
+func TestPaymentService_WaitSubmission_ContextDone(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fetches := 0
+	mux.HandleFunc("/v1/transaction/payments/p/submissions/s", func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if fetches == 2 {
+			// The second fetch either fails or returns before the next
+			// interval; both end the wait with the submission fetched.
+			cancel()
+		}
+		fmt.Fprint(w, `{"data":{"id":"s","attributes":{"status":"submitted"}}}`)
+	})
+
+	submission, _, err := client.Payment.WaitSubmission(ctx, "p", "s", time.Millisecond)
+	if err != context.Canceled {
+		t.Errorf("Payment.WaitSubmission returned %v, want %v", err, context.Canceled)
+	}
+	if submission == nil || submission.Attributes.Status != PaymentSubmissionStatusSubmitted {
+		t.Errorf("Payment.WaitSubmission returned %+v, want the last fetched submission", submission)
+	}
+}
+
+func TestPaymentService_WaitSubmission_DeadlineDuringFirstFetch(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/submissions/s", func(w http.ResponseWriter, r *http.Request) {
+		// Never respond, so that the deadline expires during the fetch.
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	submission, _, err := client.Payment.WaitSubmission(ctx, "p", "s", time.Millisecond)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Payment.WaitSubmission returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if submission != nil {
+		t.Errorf("Payment.WaitSubmission returned %+v, want nil", submission)
+	}
+}
@@ -0,0 +1,177 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate limit response headers.
+const (
+	headerRateLimit     = "X-RateLimit-Limit"
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+)
+
+// Rate is the rate limit of the API as reported by a response. Its fields
+// are zero if the API did not report them.
+type Rate struct {
+	Limit     int       // requests allowed in the current window
+	Remaining int       // requests left in the current window
+	Reset     time.Time // end of the current window
+}
+
+// parseRate parses the X-RateLimit-* headers of h. X-RateLimit-Reset is
+// either a Unix time or a number of seconds from now. ok is false if none
+// of the headers is present.
+func parseRate(h http.Header, now time.Time) (r Rate, ok bool) {
+	if v, err := strconv.Atoi(h.Get(headerRateLimit)); err == nil {
+		r.Limit, ok = v, true
+	}
+	if v, err := strconv.Atoi(h.Get(headerRateRemaining)); err == nil {
+		r.Remaining, ok = v, true
+	}
+	if v, err := strconv.ParseInt(h.Get(headerRateReset), 10, 64); err == nil && v >= 0 {
+		if v > 1e9 {
+			r.Reset = time.Unix(v, 0)
+		} else {
+			r.Reset = now.Add(time.Duration(v) * time.Second)
+		}
+		ok = true
+	}
+	return r, ok
+}
+
+// WithRateLimit makes the client send at most rps requests per second, with
+// bursts of up to burst requests, across all its services. Retries count as
+// requests.
+//
+// The limit adapts to the responses of the API: the requests remaining in
+// the window reported by the X-RateLimit-* headers are spread until its
+// reset, no request is sent before the reset once none remain, and none
+// before the delay of the Retry-After header of a 429 response.
+//
+// A request waits for its turn unless its context would expire first, in
+// which case it fails at once with a *RateLimitError.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) error {
+		if rps <= 0 || math.IsInf(rps, 0) || math.IsNaN(rps) {
+			return fmt.Errorf("rate limit must be positive, got %v", rps)
+		}
+		if burst < 1 {
+			return errors.New("rate limit burst must be at least 1")
+		}
+		c.limiter = newRateLimiter(rps, burst, time.Now)
+		return nil
+	}
+}
+
+// rateLimiter is a token bucket shared by the requests of a client.
+type rateLimiter struct {
+	rate  float64 // tokens per second, as configured
+	burst float64
+	now   func() time.Time
+
+	mu           sync.Mutex
+	tokens       float64 // may be negative when requests are waiting
+	last         time.Time
+	adapted      float64 // lower rate until adaptedUntil, from the API
+	adaptedUntil time.Time
+	blockedUntil time.Time // no request is sent before
+}
+
+func newRateLimiter(rps float64, burst int, now func() time.Time) *rateLimiter {
+	return &rateLimiter{rate: rps, burst: float64(burst), now: now, tokens: float64(burst), last: now()}
+}
+
+// currentRate returns the rate in effect at now.
+func (l *rateLimiter) currentRate(now time.Time) float64 {
+	if now.Before(l.adaptedUntil) && l.adapted < l.rate {
+		return l.adapted
+	}
+	return l.rate
+}
+
+// refill adds the tokens accumulated since the last refill. l.mu is held.
+func (l *rateLimiter) refill(now time.Time) {
+	if d := now.Sub(l.last); d > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+d.Seconds()*l.currentRate(now))
+		l.last = now
+	}
+}
+
+// wait takes a token for req, waiting until one is available. If ctx would
+// expire before, it returns a *RateLimitError at once.
+func (l *rateLimiter) wait(ctx context.Context, req *http.Request) error {
+	l.mu.Lock()
+	now := l.now()
+	l.refill(now)
+	var d time.Duration
+	if l.tokens < 1 {
+		d = time.Duration((1 - l.tokens) / l.currentRate(now) * float64(time.Second))
+	}
+	if b := l.blockedUntil.Sub(now); b > d {
+		d = b
+	}
+	if deadline, ok := ctx.Deadline(); ok && d > 0 && now.Add(d).After(deadline) {
+		l.mu.Unlock()
+		return &RateLimitError{
+			ErrorResponse: &ErrorResponse{
+				ErrorMessage: fmt.Sprintf("client rate limit: request would wait %v, beyond its deadline", d),
+				Method:       req.Method,
+				URL:          req.URL.String(),
+			},
+			RetryAfter: d,
+		}
+	}
+	l.tokens--
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// update adapts the limiter to the rate limit headers of resp.
+func (l *rateLimiter) update(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.refill(now)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok && now.Add(d).After(l.blockedUntil) {
+			l.blockedUntil = now.Add(d)
+		}
+	}
+
+	rate, ok := parseRate(resp.Header, now)
+	if !ok || resp.Header.Get(headerRateRemaining) == "" || !rate.Reset.After(now) {
+		return
+	}
+	if rate.Remaining <= 0 {
+		if rate.Reset.After(l.blockedUntil) {
+			l.blockedUntil = rate.Reset
+		}
+		return
+	}
+	l.tokens = math.Min(l.tokens, float64(rate.Remaining))
+	l.adapted = float64(rate.Remaining) / rate.Reset.Sub(now).Seconds()
+	l.adaptedUntil = rate.Reset
+}
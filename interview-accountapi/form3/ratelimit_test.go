@@ -0,0 +1,150 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	now := time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC)
+
+	h := http.Header{}
+	if _, ok := parseRate(h, now); ok {
+		t.Errorf("parseRate of no headers returned ok")
+	}
+
+	h.Set("X-RateLimit-Limit", "100")
+	h.Set("X-RateLimit-Remaining", "42")
+	h.Set("X-RateLimit-Reset", "30")
+	r, ok := parseRate(h, now)
+	if want := (Rate{100, 42, now.Add(30 * time.Second)}); !ok || r != want {
+		t.Errorf("parseRate returned %+v, want %+v", r, want)
+	}
+
+	h.Set("X-RateLimit-Reset", fmt.Sprint(now.Add(time.Minute).Unix()))
+	if r, _ := parseRate(h, now); !r.Reset.Equal(now.Add(time.Minute)) {
+		t.Errorf("parseRate of a Unix reset returned %v", r.Reset)
+	}
+}
+
+// testLimiter returns a limiter whose clock is advanced by hand.
+func testLimiter(rps float64, burst int) (*rateLimiter, *time.Time) {
+	now := time.Date(2020, 11, 11, 10, 0, 0, 0, time.UTC)
+	return newRateLimiter(rps, burst, func() time.Time { return now }), &now
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l, now := testLimiter(10, 2)
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+
+	// The deadline leaves no time to wait for a token.
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(50*time.Millisecond))
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if err := l.wait(ctx, req); err != nil {
+			t.Fatalf("wait %d within the burst returned error: %v", i, err)
+		}
+	}
+	err := l.wait(ctx, req)
+	if !IsRateLimited(err) {
+		t.Fatalf("wait beyond the burst returned %v, want a rate limit error", err)
+	}
+	if e := err.(*RateLimitError); e.RetryAfter != 100*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want 100ms", e.RetryAfter)
+	}
+
+	*now = now.Add(40 * time.Millisecond)
+	if err := l.wait(ctx, req); !IsRateLimited(err) {
+		t.Errorf("wait 40ms later returned %v, want a rate limit error", err)
+	}
+	*now = now.Add(60 * time.Millisecond)
+	if err := l.wait(ctx, req); err != nil {
+		t.Errorf("wait after a refill returned error: %v", err)
+	}
+}
+
+func TestRateLimiter_Update(t *testing.T) {
+	l, now := testLimiter(100, 10)
+	start := *now
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Remaining", "5")
+	resp.Header.Set("X-RateLimit-Reset", "10")
+	l.update(resp)
+	if l.tokens != 5 || l.currentRate(*now) != 0.5 {
+		t.Errorf("After update tokens = %v, rate = %v, want 5, 0.5", l.tokens, l.currentRate(*now))
+	}
+	if r := l.currentRate(start.Add(10 * time.Second)); r != 100 {
+		t.Errorf("Rate after the reset = %v, want 100", r)
+	}
+
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	l.update(resp)
+	if !l.blockedUntil.Equal(start.Add(10 * time.Second)) {
+		t.Errorf("blockedUntil = %v, want the reset", l.blockedUntil)
+	}
+
+	resp = &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "60")
+	l.update(resp)
+	if !l.blockedUntil.Equal(start.Add(time.Minute)) {
+		t.Errorf("blockedUntil = %v, want Retry-After", l.blockedUntil)
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	if err := WithRateLimit(1000, 1)(client); err != nil {
+		t.Fatalf("WithRateLimit returned error: %v", err)
+	}
+
+	calls := 0
+	mux.HandleFunc("/v1/organisation/accounts/a", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "1000")
+		w.Header().Set("X-RateLimit-Remaining", "999")
+		if calls > 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"a"}}`)
+	})
+
+	_, _, resp, err := client.Account.Fetch(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Account.Fetch returned error: %v", err)
+	}
+	if resp.Rate.Limit != 1000 || resp.Rate.Remaining != 999 {
+		t.Errorf("Response.Rate = %+v", resp.Rate)
+	}
+
+	if _, _, _, err := client.Account.Fetch(context.Background(), "a"); !IsRateLimited(err) {
+		t.Fatalf("Account.Fetch returned %v, want a rate limit error", err)
+	}
+
+	// The client waits for Retry-After, which is beyond the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, _, _, err = client.Account.Fetch(ctx, "a")
+	if !IsRateLimited(err) || calls != 2 {
+		t.Errorf("Account.Fetch returned %v after %d calls, want a rate limit error without a call", err, calls)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Account.Fetch waited %v, want it to fail fast", time.Since(start))
+	}
+}
+
+func TestWithRateLimit_Invalid(t *testing.T) {
+	for _, opt := range []ClientOption{WithRateLimit(0, 1), WithRateLimit(-1, 1), WithRateLimit(1, 0)} {
+		if _, err := NewClient(opt); err == nil {
+			t.Errorf("NewClient with an invalid rate limit returned no error")
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RecallService handles the recalls of payments: the sending bank asks the
+// beneficiary bank to send a payment back, which it may accept or reject
+// with a recall decision.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-recalls
+type RecallService service
+
+// RecallReasonCode is the ISO 20022 reason code of a recall.
+type RecallReasonCode string
+
+const (
+	RecallReasonDuplicate              RecallReasonCode = "DUPL"
+	RecallReasonTechnicalProblem       RecallReasonCode = "TECH"
+	RecallReasonFraud                  RecallReasonCode = "FRAD"
+	RecallReasonRequestedByCustomer    RecallReasonCode = "CUST"
+	RecallReasonIncorrectAccountNumber RecallReasonCode = "AC03"
+	RecallReasonWrongAmount            RecallReasonCode = "AM09"
+)
+
+type RecallAttributes struct {
+	Reason     string           `json:"reason,omitempty"` // free text
+	ReasonCode RecallReasonCode `json:"reason_code"`
+}
+
+type Recall struct {
+	Attributes     *RecallAttributes `json:"attributes"`
+	CreatedOn      time.Time         `json:"created_on"`
+	ID             string            `json:"id"`
+	ModifiedOn     time.Time         `json:"modified_on"`
+	OrganisationID string            `json:"organisation_id"`
+	Type           string            `json:"type"`
+	Version        int               `json:"version"`
+}
+
+type RecallResponse struct {
+	Data  *Recall       `json:"data"`
+	Links *PaymentLinks `json:"links"`
+}
+
+type RecallCreateRequestData struct {
+	Attributes     *RecallAttributes `json:"attributes"`
+	OrganisationID string            `json:"organisation_id"`
+	ID             string            `json:"id"`
+	Type           string            `json:"type"`
+}
+
+type RecallCreateRequest struct {
+	Data *RecallCreateRequestData `json:"data"`
+}
+
+// Recall decision answers.
+const (
+	RecallDecisionAccepted = "accepted"
+	RecallDecisionRejected = "rejected"
+)
+
+type RecallDecisionAttributes struct {
+	Answer string `json:"answer"`           // RecallDecisionAccepted or RecallDecisionRejected
+	Reason string `json:"reason,omitempty"` // free text, e.g. why the recall is rejected
+}
+
+// RecallDecision is the answer of the beneficiary bank to a recall.
+type RecallDecision struct {
+	Attributes     *RecallDecisionAttributes `json:"attributes"`
+	CreatedOn      time.Time                 `json:"created_on"`
+	ID             string                    `json:"id"`
+	ModifiedOn     time.Time                 `json:"modified_on"`
+	OrganisationID string                    `json:"organisation_id"`
+	Type           string                    `json:"type"`
+	Version        int                       `json:"version"`
+}
+
+type RecallDecisionResponse struct {
+	Data  *RecallDecision `json:"data"`
+	Links *PaymentLinks   `json:"links"`
+}
+
+type RecallDecisionCreateRequestData struct {
+	Attributes     *RecallDecisionAttributes `json:"attributes"`
+	OrganisationID string                    `json:"organisation_id"`
+	ID             string                    `json:"id"`
+	Type           string                    `json:"type"`
+}
+
+type RecallDecisionCreateRequest struct {
+	Data *RecallDecisionCreateRequestData `json:"data"`
+}
+
+// Create creates a recall with the given id of the payment paymentID.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-recalls-create
+func (s *RecallService) Create(ctx context.Context, paymentID string, id string, organizationId string, attributes *RecallAttributes) (*Recall, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Recall.Create")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/recalls", paymentID)
+
+	req, err := s.client.NewRequest("POST", u,
+		&RecallCreateRequest{&RecallCreateRequestData{
+			attributes,
+			organizationId,
+			id,
+			"recalls"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *RecallResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-recalls-fetch
+func (s *RecallService) Fetch(ctx context.Context, paymentID string, id string) (*Recall, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Recall.Fetch")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/recalls/%s", paymentID, id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *RecallResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// CreateSubmission submits the recall recallID to the payment scheme.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-recalls-create-recall-submission
+func (s *RecallService) CreateSubmission(ctx context.Context, paymentID string, recallID string, submissionID string, organizationId string) (*PaymentSubmission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Recall.CreateSubmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/recalls/%s/submissions", paymentID, recallID)
+
+	req, err := s.client.NewRequest("POST", u,
+		&PaymentSubmissionCreateRequest{&PaymentSubmissionCreateRequestData{
+			organizationId,
+			submissionID,
+			"recall_submissions"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentSubmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-recalls-fetch-recall-submission
+func (s *RecallService) FetchSubmission(ctx context.Context, paymentID string, recallID string, submissionID string) (*PaymentSubmission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Recall.FetchSubmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/recalls/%s/submissions/%s", paymentID, recallID, submissionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentSubmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// FetchAdmission fetches the admission of an inbound recall.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-recalls-fetch-recall-admission
+func (s *RecallService) FetchAdmission(ctx context.Context, paymentID string, recallID string, admissionID string) (*Admission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Recall.FetchAdmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/recalls/%s/admissions/%s", paymentID, recallID, admissionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *AdmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// CreateDecision answers the inbound recall recallID, accepting or rejecting
+// it.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-recalls-create-recall-decision
+func (s *RecallService) CreateDecision(ctx context.Context, paymentID string, recallID string, decisionID string, organizationId string, attributes *RecallDecisionAttributes) (*RecallDecision, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Recall.CreateDecision")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/recalls/%s/decisions", paymentID, recallID)
+
+	req, err := s.client.NewRequest("POST", u,
+		&RecallDecisionCreateRequest{&RecallDecisionCreateRequestData{
+			attributes,
+			organizationId,
+			decisionID,
+			"recall_decisions"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *RecallDecisionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// FetchDecision fetches the decision on the recall recallID, e.g. the answer
+// of the beneficiary bank to an outbound recall.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-recalls-fetch-recall-decision
+func (s *RecallService) FetchDecision(ctx context.Context, paymentID string, recallID string, decisionID string) (*RecallDecision, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Recall.FetchDecision")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/recalls/%s/decisions/%s", paymentID, recallID, decisionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *RecallDecisionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
@@ -0,0 +1,110 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestRecallService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/recalls", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(RecallCreateRequest)
+		json.NewDecoder(r.Body).Decode(v)
+		if v.Data.Type != "recalls" || v.Data.Attributes.ReasonCode != RecallReasonDuplicate || v.Data.Attributes.Reason != "sent twice" {
+			t.Errorf("Request body = %+v", v.Data)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"c","type":"recalls","attributes":{"reason":"sent twice","reason_code":"DUPL"}}}`)
+	})
+	mux.HandleFunc("/v1/transaction/payments/p/recalls/c", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id":"c","type":"recalls","attributes":{"reason_code":"DUPL"}}}`)
+	})
+	mux.HandleFunc("/v1/transaction/payments/p/recalls/c/submissions/s", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id":"s","attributes":{"status":"delivery_failed","status_reason":"rejected"}}}`)
+	})
+
+	ctx := context.Background()
+	recall, _, _, err := client.Recall.Create(ctx, "p", "c", "o",
+		&RecallAttributes{Reason: "sent twice", ReasonCode: RecallReasonDuplicate})
+	if err != nil {
+		t.Fatalf("Recall.Create returned error: %v", err)
+	}
+	if recall.Attributes.ReasonCode != RecallReasonDuplicate {
+		t.Errorf("Recall.Create returned %+v", recall.Attributes)
+	}
+
+	recall, _, _, err = client.Recall.Fetch(ctx, "p", "c")
+	if err != nil || recall.ID != "c" {
+		t.Fatalf("Recall.Fetch returned %+v, %v", recall, err)
+	}
+
+	submission, _, _, err := client.Recall.FetchSubmission(ctx, "p", "c", "s")
+	if err != nil {
+		t.Fatalf("Recall.FetchSubmission returned error: %v", err)
+	}
+	if submission.Attributes.StatusReason != "rejected" {
+		t.Errorf("Recall.FetchSubmission returned %+v", submission.Attributes)
+	}
+}
+
+func TestRecallService_Create_Validation(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/recalls", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error_message":"validation failure"}`)
+	})
+
+	_, _, _, err := client.Recall.Create(context.Background(), "p", "c", "o", &RecallAttributes{})
+	if !IsValidation(err) {
+		t.Errorf("Recall.Create returned %v, want a validation error", err)
+	}
+}
+
+func TestRecallService_CreateDecision(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/recalls/c/decisions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(RecallDecisionCreateRequest)
+		json.NewDecoder(r.Body).Decode(v)
+		if v.Data.ID != "d" || v.Data.Type != "recall_decisions" || v.Data.OrganisationID != "o" ||
+			v.Data.Attributes.Answer != RecallDecisionRejected || v.Data.Attributes.Reason != "funds spent" {
+			t.Errorf("Request body = %+v", v.Data)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"d","type":"recall_decisions","attributes":{"answer":"rejected","reason":"funds spent"}}}`)
+	})
+	mux.HandleFunc("/v1/transaction/payments/p/recalls/c/decisions/d", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id":"d","type":"recall_decisions","attributes":{"answer":"rejected"}}}`)
+	})
+
+	ctx := context.Background()
+	decision, _, _, err := client.Recall.CreateDecision(ctx, "p", "c", "d", "o",
+		&RecallDecisionAttributes{Answer: RecallDecisionRejected, Reason: "funds spent"})
+	if err != nil {
+		t.Fatalf("Recall.CreateDecision returned error: %v", err)
+	}
+	if decision.ID != "d" || decision.Attributes.Answer != RecallDecisionRejected {
+		t.Errorf("Recall.CreateDecision returned %+v", decision)
+	}
+
+	decision, _, _, err = client.Recall.FetchDecision(ctx, "p", "c", "d")
+	if err != nil {
+		t.Fatalf("Recall.FetchDecision returned error: %v", err)
+	}
+	if got := RecallState(nil, decision); got != PaymentStateDelivered {
+		t.Errorf("RecallState of a rejected recall = %v, want %v", got, PaymentStateDelivered)
+	}
+}
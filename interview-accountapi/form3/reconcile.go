@@ -0,0 +1,81 @@
+package form3
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// AccountMismatchError is returned by AccountService.CreateOrGet when an
+// account with the requested ID exists with another organisation or other
+// attributes. It matches ErrConflict.
+type AccountMismatchError struct {
+	Account *Account // existing account
+	Fields  []string // JSON names of the differing fields, sorted
+	Err     error    // conflict returned by the API
+}
+
+func (e *AccountMismatchError) Error() string {
+	return fmt.Sprintf("account %v exists with different %v", e.Account.ID, strings.Join(e.Fields, ", "))
+}
+
+func (e *AccountMismatchError) Unwrap() error { return e.Err }
+
+// accountDiff returns the JSON names of the fields of the requested account
+// differing in the existing account a. Attributes not set in the request are
+// not compared, and attributes missing from a equal zero values.
+func accountDiff(organisationID string, attributes *AccountCreateRequestAttributes, a *Account) []string {
+	var fields []string
+	if organisationID != a.OrganisationID {
+		fields = append(fields, "organisation_id")
+	}
+
+	want, err1 := jsonObject(attributes)
+	got, err2 := jsonObject(a.Attributes)
+	if err1 != nil || err2 != nil {
+		return append(fields, "attributes")
+	}
+	for k, w := range want {
+		g, ok := got[k]
+		if !ok && isZeroJSON(w) || reflect.DeepEqual(w, g) {
+			continue
+		}
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// jsonObject returns v encoded to JSON and decoded as a map.
+func jsonObject(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// isZeroJSON reports whether v is a zero value omitted by omitempty.
+func isZeroJSON(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
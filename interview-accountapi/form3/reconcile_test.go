@@ -0,0 +1,95 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/vslovik/form3/form3test"
+)
+
+func TestAccountDiff(t *testing.T) {
+	existing := &Account{
+		OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		Attributes: &AccountAttributes{
+			BankID:       "400300",
+			Country:      "GB",
+			Iban:         testIBAN,
+			Name:         []string{"Samantha Holder"},
+			JointAccount: false,
+		},
+	}
+	tests := []struct {
+		organisationID string
+		attributes     *AccountCreateRequestAttributes
+		want           []string
+	}{
+		{"eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+			&AccountCreateRequestAttributes{BankID: "400300", Country: "GB", Name: []string{"Samantha Holder"}, JointAccount: Bool(false)},
+			nil},
+		{"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+			&AccountCreateRequestAttributes{BankID: "400301", Country: "GB", Name: []string{"Sam Holder"}, JointAccount: Bool(true)},
+			[]string{"bank_id", "joint_account", "name", "organisation_id"}},
+		{"eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+			&AccountCreateRequestAttributes{Country: "GB", CustomerID: "234"},
+			[]string{"customer_id"}},
+	}
+	for _, tt := range tests {
+		if got := accountDiff(tt.organisationID, tt.attributes, existing); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("accountDiff(%+v) = %v, want %v", tt.attributes, got, tt.want)
+		}
+	}
+}
+
+func TestAccountService_CreateOrGet(t *testing.T) {
+	srv := form3test.NewServer()
+	defer srv.Close()
+	client, _ := NewClient(WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	const (
+		accountID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+		orgID     = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	)
+	attributes := func() *AccountCreateRequestAttributes {
+		return &AccountCreateRequestAttributes{
+			BankID:       "400300",
+			BankIDCode:   "GBDSC",
+			Bic:          "NWBKGB22",
+			Country:      "GB",
+			JointAccount: Bool(false),
+		}
+	}
+
+	created, _, resp, err := client.Account.CreateOrGet(ctx, accountID, orgID, attributes())
+	if err != nil {
+		t.Fatalf("Account.CreateOrGet returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Account.CreateOrGet of a new account returned status %v, want 201", resp.StatusCode)
+	}
+
+	got, links, resp, err := client.Account.CreateOrGet(ctx, accountID, orgID, attributes())
+	if err != nil {
+		t.Fatalf("Account.CreateOrGet of an existing account returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || got.ID != created.ID || links.Self == "" {
+		t.Errorf("Account.CreateOrGet of an existing account returned %+v, %+v, status %v", got, links, resp.StatusCode)
+	}
+
+	other := attributes()
+	other.Bic = "BARCGB22"
+	_, _, _, err = client.Account.CreateOrGet(ctx, accountID, orgID, other)
+	var mismatch *AccountMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Account.CreateOrGet with other attributes returned %v, want an *AccountMismatchError", err)
+	}
+	if !reflect.DeepEqual(mismatch.Fields, []string{"bic"}) || mismatch.Account.ID != accountID {
+		t.Errorf("AccountMismatchError is %+v", mismatch)
+	}
+	if !IsConflict(err) {
+		t.Errorf("AccountMismatchError does not match ErrConflict")
+	}
+}
@@ -15,6 +15,24 @@ type ListOptions struct {
 	PerPage int `url:"page[size],omitempty"`
 }
 
+// AccountListFilter specifies the server-side filters of the account list.
+// Several values given for one filter are matched as alternatives.
+type AccountListFilter struct {
+	BankID        []string `url:"filter[bank_id],omitempty,comma"`
+	BankIDCode    []string `url:"filter[bank_id_code],omitempty,comma"`
+	AccountNumber []string `url:"filter[account_number],omitempty,comma"`
+	Iban          []string `url:"filter[iban],omitempty,comma"`
+	CustomerID    []string `url:"filter[customer_id],omitempty,comma"`
+	Country       []string `url:"filter[country],omitempty,comma"`
+}
+
+// AccountListOptions specifies the optional parameters to the
+// AccountService.List method.
+type AccountListOptions struct {
+	ListOptions
+	AccountListFilter
+}
+
 // addOptions adds the parameters in opts as URL query parameters to s. opts
 // must be a struct whose fields may contain "url" tags.
 func addOptions(s string, opts interface{}) (string, error) {
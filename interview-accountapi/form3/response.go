@@ -2,50 +2,246 @@ package form3
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
-// Response is a Form3 API response. This wraps the standard http.Response.
+// Headers carrying the ID the API assigned to a request.
+const (
+	headerRequestID     = "X-Request-Id"
+	headerCorrelationID = "X-Correlation-Id"
+)
+
+// NoPage is the page number of Response for a pagination link that is
+// missing or has no numeric page number.
+const NoPage = -1
+
+// Response is a Form3 API response. This wraps the standard http.Response
+// and provides convenient access to things like the request ID, the rate
+// limit and the pagination links.
 type Response struct {
 	*http.Response
+
+	// RequestID is the ID the API assigned to the request, from the
+	// X-Request-Id header or else the X-Correlation-Id header.
+	RequestID string
+
+	Rate Rate // zero if the API did not report it
+
+	// Page numbers of the pagination links of a list response, NoPage for
+	// other responses. The first page is page 0. The API gives the last
+	// link as page[number]=last rather than a number, in which case
+	// LastPage is NoPage and the last page is found by following
+	// LastPageURL.
+	FirstPage int
+	PrevPage  int
+	NextPage  int
+	LastPage  int
+
+	// LastPageURL is the last link of a list response, "" for other
+	// responses.
+	LastPageURL string
+
+	// Duration is the time spent in Do until the response headers were
+	// received, including the middleware, rate limiting waits and retries.
+	Duration time.Duration
 }
 
+// newResponse creates a new Response for the provided http.Response.
+// r must not be nil.
+func newResponse(r *http.Response) *Response {
+	response := &Response{
+		Response:  r,
+		RequestID: requestID(r.Header),
+		FirstPage: NoPage,
+		PrevPage:  NoPage,
+		NextPage:  NoPage,
+		LastPage:  NoPage,
+	}
+	response.Rate, _ = parseRate(r.Header, time.Now())
+	return response
+}
+
+// requestID returns the ID the API assigned to a request from the headers
+// of its response.
+func requestID(h http.Header) string {
+	if id := h.Get(headerRequestID); id != "" {
+		return id
+	}
+	return h.Get(headerCorrelationID)
+}
+
+// populatePageValues sets the page numbers of r from the pagination links
+// of a list response.
+func (r *Response) populatePageValues(links *ListLinks) {
+	if links == nil {
+		return
+	}
+	r.FirstPage = pageNumber(links.First)
+	r.PrevPage = pageNumber(links.Prev)
+	r.NextPage = pageNumber(links.Next)
+	r.LastPage = pageNumber(links.Last)
+	r.LastPageURL = links.Last
+}
+
+// pageNumber returns the page[number] parameter of the pagination link u,
+// or NoPage. The API may use "first" for page 0; "last" cannot be resolved
+// to a number without fetching the page and is NoPage.
+func pageNumber(u string) int {
+	if u == "" {
+		return NoPage
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return NoPage
+	}
+	v := parsed.Query().Get("page[number]")
+	if v == "first" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return NoPage
+	}
+	return n
+}
+
+// Sentinel errors matched by the typed API errors with errors.Is.
+var (
+	ErrNotFound    = errors.New("form3: resource not found")
+	ErrConflict    = errors.New("form3: conflict")
+	ErrValidation  = errors.New("form3: validation failed")
+	ErrRateLimited = errors.New("form3: rate limited")
+	ErrServer      = errors.New("form3: server error")
+)
+
 /*
-An ErrorResponse reports an error caused by an API request.
+An ErrorResponse reports an error caused by an API request. More specific
+errors wrap it, depending on the status code of the response:
+
+	400, 422  *ValidationError
+	404       *NotFoundError
+	409       *ConflictError
+	429       *RateLimitError
+	5xx       *ServerError
+
+Other status codes are reported as a bare *ErrorResponse.
 */
 type ErrorResponse struct {
 	ErrorMessage string `json:"error_message"` // error message
-}
+	ErrorCode    string `json:"error_code"`    // error code, if the API sent one
 
-// newResponse creates a new Response for the provided http.Response.
-// r must not be nil.
-func newResponse(r *http.Response) *Response {
-	return &Response{Response: r}
+	StatusCode int    `json:"-"` // HTTP status code of the response
+	RequestID  string `json:"-"` // ID the API assigned to the request
+	Method     string `json:"-"` // HTTP method of the request
+	URL        string `json:"-"` // URL of the request
+	Body       []byte `json:"-"` // raw response body
 }
 
 func (r *ErrorResponse) Error() string {
-	return fmt.Sprintf("%v", r.ErrorMessage)
+	if r.Method == "" {
+		return r.ErrorMessage
+	}
+	return fmt.Sprintf("%v %v: %d %v", r.Method, r.URL, r.StatusCode, r.ErrorMessage)
 }
 
+// NotFoundError is returned when the requested resource does not exist.
+type NotFoundError struct{ *ErrorResponse }
+
+func (e *NotFoundError) Unwrap() error        { return e.ErrorResponse }
+func (e *NotFoundError) Is(target error) bool { return target == ErrNotFound }
+
+// ConflictError is returned when a request conflicts with the current state
+// of the resource, e.g. a duplicate ID or a version mismatch.
+type ConflictError struct{ *ErrorResponse }
+
+func (e *ConflictError) Unwrap() error        { return e.ErrorResponse }
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+// ValidationError is returned when the API rejects the request as invalid.
+type ValidationError struct{ *ErrorResponse }
+
+func (e *ValidationError) Unwrap() error        { return e.ErrorResponse }
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// RateLimitError is returned when the API throttles the client.
+type RateLimitError struct {
+	*ErrorResponse
+	RetryAfter time.Duration // delay requested by the API, if any
+}
+
+func (e *RateLimitError) Unwrap() error        { return e.ErrorResponse }
+func (e *RateLimitError) Is(target error) bool { return target == ErrRateLimited }
+
+// ServerError is returned when the API fails with a 5xx status code.
+type ServerError struct{ *ErrorResponse }
+
+func (e *ServerError) Unwrap() error        { return e.ErrorResponse }
+func (e *ServerError) Is(target error) bool { return target == ErrServer }
+
+// IsNotFound reports whether err is, or wraps, a *NotFoundError.
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsConflict reports whether err is, or wraps, a *ConflictError.
+func IsConflict(err error) bool { return errors.Is(err, ErrConflict) }
+
+// IsValidation reports whether err is, or wraps, a *ValidationError.
+func IsValidation(err error) bool { return errors.Is(err, ErrValidation) }
+
+// IsRateLimited reports whether err is, or wraps, a *RateLimitError.
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// IsServerError reports whether err is, or wraps, a *ServerError.
+func IsServerError(err error) bool { return errors.Is(err, ErrServer) }
+
 // CheckResponse checks the API response for errors, and returns them if
 // present. A response is considered an error if it has a status code outside
-// the 200 range
-// API error responses are expected to have response
-// body, and a JSON response body that maps to ErrorResponse.
+// the 200 range.
+// API error responses are expected to have response body, and a JSON
+// response body that maps to ErrorResponse. If the body cannot be decoded,
+// the status text is used as the error message.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
-	errorResponse := &ErrorResponse{}
-	data, err := ioutil.ReadAll(r.Body)
+	errorResponse := &ErrorResponse{
+		StatusCode: r.StatusCode,
+		RequestID:  requestID(r.Header),
+	}
+	if r.Request != nil {
+		errorResponse.Method = r.Request.Method
+		errorResponse.URL = r.Request.URL.String()
+	}
 
-	if err == nil && data != nil {
-		err = json.Unmarshal(data, errorResponse)
-		if err != nil && r.StatusCode == 404 {
-			return &ErrorResponse{ErrorMessage: "Account not found"}
+	data, err := ioutil.ReadAll(r.Body)
+	if err == nil && len(data) > 0 {
+		errorResponse.Body = data
+		if json.Unmarshal(data, errorResponse) != nil {
+			errorResponse.ErrorMessage = ""
+			errorResponse.ErrorCode = ""
 		}
 	}
+	if errorResponse.ErrorMessage == "" {
+		errorResponse.ErrorMessage = http.StatusText(r.StatusCode)
+	}
+
+	switch c := r.StatusCode; {
+	case c == http.StatusBadRequest || c == http.StatusUnprocessableEntity:
+		return &ValidationError{errorResponse}
+	case c == http.StatusNotFound:
+		return &NotFoundError{errorResponse}
+	case c == http.StatusConflict:
+		return &ConflictError{errorResponse}
+	case c == http.StatusTooManyRequests:
+		retryAfter, _ := parseRetryAfter(r.Header.Get("Retry-After"), time.Now())
+		return &RateLimitError{ErrorResponse: errorResponse, RetryAfter: retryAfter}
+	case c >= 500:
+		return &ServerError{errorResponse}
+	}
 	return errorResponse
 }
@@ -0,0 +1,217 @@
+package form3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newErrorHTTPResponse(code int, body string) *http.Response {
+	u, _ := url.Parse("http://localhost:8080/v1/organisation/accounts/1")
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{headerRequestID: {"req-1"}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		Request:    &http.Request{Method: "GET", URL: u},
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	body := `{"error_message":"record 1 does not exist","error_code":"not_found"}`
+	err := CheckResponse(newErrorHTTPResponse(http.StatusNotFound, body))
+
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("CheckResponse returned %T, want *NotFoundError", err)
+	}
+	want := &ErrorResponse{
+		ErrorMessage: "record 1 does not exist",
+		ErrorCode:    "not_found",
+		StatusCode:   http.StatusNotFound,
+		RequestID:    "req-1",
+		Method:       "GET",
+		URL:          "http://localhost:8080/v1/organisation/accounts/1",
+		Body:         []byte(body),
+	}
+	if !reflect.DeepEqual(notFound.ErrorResponse, want) {
+		t.Errorf("CheckResponse returned %+v, want %+v", notFound.ErrorResponse, want)
+	}
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Errorf("errors.As(%T, *ErrorResponse) is false", err)
+	}
+	if got, want := err.Error(), "GET http://localhost:8080/v1/organisation/accounts/1: 404 record 1 does not exist"; got != want {
+		t.Errorf("Error() is %q, want %q", got, want)
+	}
+}
+
+func TestCheckResponse_Types(t *testing.T) {
+	tests := []struct {
+		code int
+		is   error
+		want interface{}
+	}{
+		{http.StatusBadRequest, ErrValidation, &ValidationError{}},
+		{http.StatusUnprocessableEntity, ErrValidation, &ValidationError{}},
+		{http.StatusNotFound, ErrNotFound, &NotFoundError{}},
+		{http.StatusConflict, ErrConflict, &ConflictError{}},
+		{http.StatusTooManyRequests, ErrRateLimited, &RateLimitError{}},
+		{http.StatusInternalServerError, ErrServer, &ServerError{}},
+		{http.StatusServiceUnavailable, ErrServer, &ServerError{}},
+		{http.StatusForbidden, nil, &ErrorResponse{}},
+	}
+	for _, tt := range tests {
+		err := CheckResponse(newErrorHTTPResponse(tt.code, `{"error_message":"x"}`))
+		if reflect.TypeOf(err) != reflect.TypeOf(tt.want) {
+			t.Errorf("CheckResponse(%d) returned %T, want %T", tt.code, err, tt.want)
+		}
+		if tt.is != nil && !errors.Is(err, tt.is) {
+			t.Errorf("errors.Is(CheckResponse(%d), %v) is false", tt.code, tt.is)
+		}
+	}
+}
+
+func TestCheckResponse_UnparseableBody(t *testing.T) {
+	err := CheckResponse(newErrorHTTPResponse(http.StatusNotFound, "<html>gone</html>"))
+	if !IsNotFound(err) {
+		t.Fatalf("IsNotFound(%v) is false", err)
+	}
+	var errResp *ErrorResponse
+	errors.As(err, &errResp)
+	if got, want := errResp.ErrorMessage, "Not Found"; got != want {
+		t.Errorf("ErrorMessage is %q, want %q", got, want)
+	}
+	if got, want := string(errResp.Body), "<html>gone</html>"; got != want {
+		t.Errorf("Body is %q, want %q", got, want)
+	}
+}
+
+func TestCheckResponse_RateLimit(t *testing.T) {
+	r := newErrorHTTPResponse(http.StatusTooManyRequests, "")
+	r.Header.Set("Retry-After", "3")
+
+	var rateLimit *RateLimitError
+	if err := CheckResponse(r); !errors.As(err, &rateLimit) {
+		t.Fatalf("CheckResponse returned %T, want *RateLimitError", err)
+	}
+	if got, want := rateLimit.RetryAfter, 3*time.Second; got != want {
+		t.Errorf("RetryAfter is %v, want %v", got, want)
+	}
+}
+
+func TestDo_ReturnsTypedError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error_message":"invalid version"}`)
+	})
+
+	_, _, _, err := client.Account.Fetch(context.Background(), "1")
+	if !IsConflict(err) {
+		t.Errorf("IsConflict(%v) is false", err)
+	}
+	if IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) is true", err)
+	}
+}
+
+func TestNewResponse(t *testing.T) {
+	h := http.Header{}
+	h.Set(headerCorrelationID, "corr-1")
+	h.Set(headerRateLimit, "100")
+	r := newResponse(&http.Response{Header: h})
+	if r.RequestID != "corr-1" || r.Rate.Limit != 100 {
+		t.Errorf("newResponse returned request ID %q, rate %+v", r.RequestID, r.Rate)
+	}
+	if r.FirstPage != NoPage || r.PrevPage != NoPage || r.NextPage != NoPage || r.LastPage != NoPage {
+		t.Errorf("newResponse returned pages %d %d %d %d, want NoPage", r.FirstPage, r.PrevPage, r.NextPage, r.LastPage)
+	}
+
+	r = newResponse(&http.Response{Header: http.Header{
+		headerRequestID:     {"req-1"},
+		headerCorrelationID: {"corr-1"},
+	}})
+	if r.RequestID != "req-1" {
+		t.Errorf("newResponse returned request ID %q, want req-1", r.RequestID)
+	}
+}
+
+func TestDo_ResponseMetadata(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerRequestID, "req-2")
+		time.Sleep(5 * time.Millisecond)
+		fmt.Fprint(w, `{"data":[],"links":{
+			"first":"/v1/organisation/accounts?page%5Bnumber%5D=first",
+			"last":"/v1/organisation/accounts?page%5Bnumber%5D=9",
+			"next":"/v1/organisation/accounts?page%5Bnumber%5D=3",
+			"prev":"/v1/organisation/accounts?page%5Bnumber%5D=1",
+			"self":"/v1/organisation/accounts?page%5Bnumber%5D=2"}}`)
+	})
+
+	_, _, resp, err := client.Account.List(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Account.List returned error: %v", err)
+	}
+	if resp.RequestID != "req-2" {
+		t.Errorf("Response.RequestID = %q, want req-2", resp.RequestID)
+	}
+	if got, want := []int{resp.FirstPage, resp.PrevPage, resp.NextPage, resp.LastPage}, []int{0, 1, 3, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Response pages = %v, want %v", got, want)
+	}
+	if resp.Duration < 5*time.Millisecond {
+		t.Errorf("Response.Duration = %v, want at least 5ms", resp.Duration)
+	}
+}
+
+func TestPageNumber(t *testing.T) {
+	tests := map[string]int{
+		"":                             NoPage,
+		"/v1/x":                        NoPage,
+		"/v1/x?page%5Bnumber%5D=first": 0,
+		"/v1/x?page%5Bnumber%5D=last":  NoPage,
+		"/v1/x?page[number]=4":         4,
+		"/v1/x?page%5Bnumber%5D=-2":    NoPage,
+	}
+	for u, want := range tests {
+		if got := pageNumber(u); got != want {
+			t.Errorf("pageNumber(%q) = %d, want %d", u, got, want)
+		}
+	}
+}
+
+func TestDo_ResponseMetadata_SymbolicLinks(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	// The links returned by the API, see TestAccountService_List_NoPages.
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[],"links":{
+			"first":"/v1/organisation/accounts?page%5Bnumber%5D=first",
+			"last":"/v1/organisation/accounts?page%5Bnumber%5D=last",
+			"self":"/v1/organisation/accounts"}}`)
+	})
+
+	_, _, resp, err := client.Account.List(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Account.List returned error: %v", err)
+	}
+	if got, want := []int{resp.FirstPage, resp.PrevPage, resp.NextPage, resp.LastPage}, []int{0, NoPage, NoPage, NoPage}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Response pages = %v, want %v", got, want)
+	}
+	if got, want := resp.LastPageURL, "/v1/organisation/accounts?page%5Bnumber%5D=last"; got != want {
+		t.Errorf("Response.LastPageURL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,223 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy specifies how Client.Do retries requests that failed with a
+// transport error or a retryable status code.
+//
+// A request that failed with a transport error, e.g. a timeout, or with a
+// retryable status other than 429 Too Many Requests and 503 Service
+// Unavailable, e.g. 502 Bad Gateway or 504 Gateway Timeout, may have been
+// applied by the API before the error, so it is only retried if repeating it
+// is harmless: GET, HEAD, OPTIONS, PUT and DELETE requests. POST and PATCH
+// requests are only retried then if RetryNonIdempotent is set. 429 and 503
+// responses are retried for all methods, as the API rejected the request
+// without applying it.
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first one. Values below 2
+	// disable retries.
+	MaxAttempts int
+
+	// Backoff before the first retry. It doubles with every further retry.
+	BaseBackoff time.Duration
+
+	// Upper bound of the computed backoff.
+	MaxBackoff time.Duration
+
+	// Fraction of the backoff, between 0 and 1, that is randomized to spread
+	// retries of concurrent clients.
+	Jitter float64
+
+	// Status codes that are retried.
+	RetryableStatusCodes []int
+
+	// RetryNonIdempotent retries POST and PATCH requests after transport
+	// errors and gateway errors too. A retried request may then fail with a
+	// *ConflictError if the first attempt was applied: AccountService.CreateOrGet
+	// reconciles a retried create, while a conflict after a retried update
+	// must be checked by fetching the resource, as the update carries the
+	// version the first attempt already incremented.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is a retry policy suitable for most callers.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseBackoff: 100 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+	Jitter:      0.5,
+	RetryableStatusCodes: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// WithRetryPolicy makes the client retry failed requests according to p.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		if p.Jitter < 0 || p.Jitter > 1 {
+			return errors.New("retry jitter must be between 0 and 1")
+		}
+		if p.BaseBackoff < 0 || p.MaxBackoff < 0 {
+			return errors.New("retry backoff must be non-negative")
+		}
+		c.retryPolicy = &p
+		return nil
+	}
+}
+
+// retryable reports whether the request req that ended with resp and err
+// should be attempted again.
+func (p *RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return p.RetryNonIdempotent || idempotent(req.Method)
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode != code {
+			continue
+		}
+		switch code {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		}
+		return p.RetryNonIdempotent || idempotent(req.Method)
+	}
+	return false
+}
+
+// idempotent reports whether a request with the given method may be sent
+// again after it may have been applied, see RetryPolicy.
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before the given retry, counted from 1.
+// A Retry-After header on resp takes precedence over the computed backoff.
+func (p *RetryPolicy) backoff(retry int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d
+		}
+	}
+
+	d := float64(p.BaseBackoff) * math.Pow(2, float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	d -= d * p.Jitter * rand.Float64()
+	return time.Duration(d)
+}
+
+// parseRetryAfter parses a Retry-After header value given either in seconds
+// or as an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// send sends req, retrying it according to the client's retry policy. Every
+// attempt waits for the rate limiter of the client, if any, and is counted by
+// its circuit breaker, if any. The request body is rewound and the request
+// authorized and signed again before every retry.
+// Waiting between attempts stops as soon as ctx is done.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	p := c.retryPolicy
+	attempts := 1
+	if p != nil && p.MaxAttempts > 1 {
+		attempts = p.MaxAttempts
+	}
+
+	trace := ContextClientTrace(ctx)
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		if c.tokens != nil {
+			if err := c.tokens.authorize(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		if c.signer != nil {
+			if err := c.signer.Sign(req); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.breakers != nil {
+			if err := c.breakers.allow(req.URL.Host); err != nil {
+				return nil, err
+			}
+		}
+		if trace != nil && trace.Attempt != nil {
+			trace.Attempt(attempt)
+		}
+		resp, err := c.client.Do(req)
+		if c.breakers != nil {
+			c.breakers.record(req.URL.Host, requestOutcome(ctx, resp, err))
+		}
+		if c.limiter != nil && err == nil {
+			c.limiter.update(resp)
+		}
+		if attempt >= attempts || ctx.Err() != nil || !p.retryable(req, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			// The body has been consumed and cannot be sent again.
+			return resp, err
+		}
+
+		wait := p.backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.CopyN(ioutil.Discard, resp.Body, maxBodySlurpSize)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
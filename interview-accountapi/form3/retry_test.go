@@ -0,0 +1,263 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:          3,
+	BaseBackoff:          time.Millisecond,
+	MaxBackoff:           5 * time.Millisecond,
+	Jitter:               0.5,
+	RetryableStatusCodes: DefaultRetryPolicy.RetryableStatusCodes,
+}
+
+func TestDo_RetriesRetryableStatus(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retryPolicy = &testRetryPolicy
+
+	var bodies []string
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"a68eddcd-6eec-4b5e-846d-97b1161248e2"}}`)
+	})
+
+	account, _, _, err := client.Account.Create(context.Background(),
+		"a68eddcd-6eec-4b5e-846d-97b1161248e2",
+		"d91afcdb-62d2-4185-b23d-71c98eaab812", attr)
+	if err != nil {
+		t.Fatalf("Account.Create returned error: %v", err)
+	}
+	if account.ID != "a68eddcd-6eec-4b5e-846d-97b1161248e2" {
+		t.Errorf("Account.Create returned %+v", account)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("Server received %d requests, want 3", len(bodies))
+	}
+	for i := 1; i < len(bodies); i++ {
+		if bodies[i] != bodies[0] {
+			t.Errorf("Request body of attempt %d is %q, want %q", i+1, bodies[i], bodies[0])
+		}
+	}
+}
+
+func TestDo_StopsAfterMaxAttempts(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retryPolicy = &testRetryPolicy
+
+	calls := 0
+	mux.HandleFunc("/v1/organisation/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, _, resp, err := client.Account.Fetch(context.Background(), "1")
+	if err == nil {
+		t.Fatalf("Account.Fetch returned no error")
+	}
+	if resp == nil || resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Account.Fetch returned response %v, want status 502", resp)
+	}
+	if calls != 3 {
+		t.Errorf("Server received %d requests, want 3", calls)
+	}
+}
+
+func TestDo_DoesNotRetryOtherStatus(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retryPolicy = &testRetryPolicy
+
+	calls := 0
+	mux.HandleFunc("/v1/organisation/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	if _, _, _, err := client.Account.Fetch(context.Background(), "1"); err == nil {
+		t.Fatalf("Account.Fetch returned no error")
+	}
+	if calls != 1 {
+		t.Errorf("Server received %d requests, want 1", calls)
+	}
+}
+
+func TestDo_DoesNotRetryGatewayErrorOfNonIdempotentRequest(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retryPolicy = &testRetryPolicy
+
+	calls := 0
+	mux.HandleFunc("/v1/organisation/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusGatewayTimeout)
+	})
+
+	_, _, _, err := client.Account.Update(context.Background(), "1", 0,
+		&AccountUpdateRequestAttributes{CustomerID: String("567")})
+	if err == nil {
+		t.Fatalf("Account.Update returned no error")
+	}
+	if calls != 1 {
+		t.Errorf("Server received %d requests, want 1", calls)
+	}
+
+	p := testRetryPolicy
+	p.RetryNonIdempotent = true
+	client.retryPolicy = &p
+	calls = 0
+	client.Account.Update(context.Background(), "1", 0,
+		&AccountUpdateRequestAttributes{CustomerID: String("567")})
+	if calls != 3 {
+		t.Errorf("Server received %d requests with RetryNonIdempotent, want 3", calls)
+	}
+}
+
+// closeConnection makes the requests to pattern fail with a transport error,
+// counting them.
+func closeConnection(t *testing.T, mux *http.ServeMux, pattern string) *int32 {
+	calls := new(int32)
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatalf("Hijack returned error: %v", err)
+		}
+		conn.Close()
+	})
+	return calls
+}
+
+func TestDo_RetriesTransportErrorOfIdempotentRequest(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retryPolicy = &testRetryPolicy
+
+	calls := closeConnection(t, mux, "/v1/organisation/accounts/1")
+
+	if _, _, _, err := client.Account.Fetch(context.Background(), "1"); err == nil {
+		t.Fatalf("Account.Fetch returned no error")
+	}
+	if n := atomic.LoadInt32(calls); n != 3 {
+		t.Errorf("Server received %d requests, want 3", n)
+	}
+}
+
+func TestDo_DoesNotRetryTransportErrorOfPost(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retryPolicy = &testRetryPolicy
+
+	calls := closeConnection(t, mux, "/v1/organisation/accounts")
+
+	_, _, _, err := client.Account.Create(context.Background(),
+		"a68eddcd-6eec-4b5e-846d-97b1161248e2",
+		"d91afcdb-62d2-4185-b23d-71c98eaab812", attr)
+	if err == nil {
+		t.Fatalf("Account.Create returned no error")
+	}
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Errorf("Server received %d requests, want 1", n)
+	}
+
+	p := testRetryPolicy
+	p.RetryNonIdempotent = true
+	client.retryPolicy = &p
+	atomic.StoreInt32(calls, 0)
+	client.Account.Create(context.Background(),
+		"a68eddcd-6eec-4b5e-846d-97b1161248e2",
+		"d91afcdb-62d2-4185-b23d-71c98eaab812", attr)
+	if n := atomic.LoadInt32(calls); n != 3 {
+		t.Errorf("Server received %d requests with RetryNonIdempotent, want 3", n)
+	}
+}
+
+func TestDo_RetryStopsOnContextCancel(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	p := testRetryPolicy
+	p.BaseBackoff = time.Hour
+	p.MaxBackoff = time.Hour
+	client.retryPolicy = &p
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	mux.HandleFunc("/v1/organisation/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, _, _, err := client.Account.Fetch(ctx, "1")
+	if err != context.DeadlineExceeded {
+		t.Errorf("Account.Fetch returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+	} {
+		if got := p.backoff(retry, nil); got != want {
+			t.Errorf("backoff(%d) is %v, want %v", retry, got, want)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1, nil); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("backoff(1) with jitter is %v, want between 50ms and 100ms", got)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"7"}}}
+	if got, want := p.backoff(1, resp), 7*time.Second; got != want {
+		t.Errorf("backoff with Retry-After is %v, want %v", got, want)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, time.November, 11, 10, 40, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 11 Nov 2020 10:40:30 GMT", 30 * time.Second, true},
+		{"Wed, 11 Nov 2020 10:39:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.in, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWithRetryPolicy_Invalid(t *testing.T) {
+	p := DefaultRetryPolicy
+	p.Jitter = 2
+	if _, err := NewClient(WithRetryPolicy(p)); err == nil {
+		t.Errorf("NewClient with jitter 2 returned no error")
+	}
+}
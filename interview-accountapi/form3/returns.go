@@ -0,0 +1,175 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ReturnService handles the returns of payments: a beneficiary bank sends
+// back a payment it cannot apply, with a reason code.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-returns
+type ReturnService service
+
+// ReturnCode is the ISO 20022 reason code of a return.
+type ReturnCode string
+
+const (
+	ReturnCodeIncorrectAccountNumber       ReturnCode = "AC01"
+	ReturnCodeClosedAccount                ReturnCode = "AC04"
+	ReturnCodeBlockedAccount               ReturnCode = "AC06"
+	ReturnCodeTransactionForbidden         ReturnCode = "AG01"
+	ReturnCodeWrongAmount                  ReturnCode = "AM09"
+	ReturnCodeBeneficiaryDeceased          ReturnCode = "MD07"
+	ReturnCodeNotSpecifiedReasonCustomer   ReturnCode = "MS02"
+	ReturnCodeFollowingCancellationRequest ReturnCode = "FOCR"
+	ReturnCodeRegulatoryReason             ReturnCode = "RR04"
+)
+
+type ReturnAttributes struct {
+	Amount     string     `json:"amount,omitempty"` // decimal string, the payment amount if empty
+	Currency   string     `json:"currency,omitempty"`
+	ReturnCode ReturnCode `json:"return_code"`
+}
+
+type Return struct {
+	Attributes     *ReturnAttributes `json:"attributes"`
+	CreatedOn      time.Time         `json:"created_on"`
+	ID             string            `json:"id"`
+	ModifiedOn     time.Time         `json:"modified_on"`
+	OrganisationID string            `json:"organisation_id"`
+	Type           string            `json:"type"`
+	Version        int               `json:"version"`
+}
+
+type ReturnResponse struct {
+	Data  *Return       `json:"data"`
+	Links *PaymentLinks `json:"links"`
+}
+
+type ReturnCreateRequestData struct {
+	Attributes     *ReturnAttributes `json:"attributes"`
+	OrganisationID string            `json:"organisation_id"`
+	ID             string            `json:"id"`
+	Type           string            `json:"type"`
+}
+
+type ReturnCreateRequest struct {
+	Data *ReturnCreateRequestData `json:"data"`
+}
+
+// Create creates a return with the given id of the payment paymentID.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-returns-create
+func (s *ReturnService) Create(ctx context.Context, paymentID string, id string, organizationId string, attributes *ReturnAttributes) (*Return, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Return.Create")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/returns", paymentID)
+
+	req, err := s.client.NewRequest("POST", u,
+		&ReturnCreateRequest{&ReturnCreateRequestData{
+			attributes,
+			organizationId,
+			id,
+			"returns"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *ReturnResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-returns-fetch
+func (s *ReturnService) Fetch(ctx context.Context, paymentID string, id string) (*Return, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Return.Fetch")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/returns/%s", paymentID, id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *ReturnResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// CreateSubmission submits the return returnID to the payment scheme.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-returns-create-return-submission
+func (s *ReturnService) CreateSubmission(ctx context.Context, paymentID string, returnID string, submissionID string, organizationId string) (*PaymentSubmission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Return.CreateSubmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/returns/%s/submissions", paymentID, returnID)
+
+	req, err := s.client.NewRequest("POST", u,
+		&PaymentSubmissionCreateRequest{&PaymentSubmissionCreateRequestData{
+			organizationId,
+			submissionID,
+			"return_submissions"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentSubmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-returns-fetch-return-submission
+func (s *ReturnService) FetchSubmission(ctx context.Context, paymentID string, returnID string, submissionID string) (*PaymentSubmission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Return.FetchSubmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/returns/%s/submissions/%s", paymentID, returnID, submissionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentSubmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// FetchAdmission fetches the admission of an inbound return.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-returns-fetch-return-admission
+func (s *ReturnService) FetchAdmission(ctx context.Context, paymentID string, returnID string, admissionID string) (*Admission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Return.FetchAdmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/returns/%s/admissions/%s", paymentID, returnID, admissionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *AdmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
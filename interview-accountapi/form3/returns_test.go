@@ -0,0 +1,91 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestReturnService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/returns", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(ReturnCreateRequest)
+		json.NewDecoder(r.Body).Decode(v)
+		if v.Data.ID != "r" || v.Data.Type != "returns" || v.Data.Attributes.ReturnCode != ReturnCodeClosedAccount {
+			t.Errorf("Request body = %+v", v.Data)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"r","type":"returns","attributes":{"return_code":"AC04"}},
+			"links":{"self":"/v1/transaction/payments/p/returns/r"}}`)
+	})
+
+	ret, links, _, err := client.Return.Create(context.Background(), "p", "r", "o",
+		&ReturnAttributes{ReturnCode: ReturnCodeClosedAccount})
+	if err != nil {
+		t.Fatalf("Return.Create returned error: %v", err)
+	}
+	if ret.Attributes.ReturnCode != ReturnCodeClosedAccount {
+		t.Errorf("Return.Create returned %+v", ret.Attributes)
+	}
+	if links.Self != "/v1/transaction/payments/p/returns/r" {
+		t.Errorf("Return.Create returned links %+v", links)
+	}
+}
+
+func TestReturnService_Submission(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/returns/r/submissions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(PaymentSubmissionCreateRequest)
+		json.NewDecoder(r.Body).Decode(v)
+		if v.Data.ID != "s" || v.Data.Type != "return_submissions" {
+			t.Errorf("Request body = %+v", v.Data)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"s","attributes":{"status":"accepted"}}}`)
+	})
+	mux.HandleFunc("/v1/transaction/payments/p/returns/r/submissions/s", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id":"s","attributes":{"status":"delivery_confirmed"}}}`)
+	})
+
+	ctx := context.Background()
+	if _, _, _, err := client.Return.CreateSubmission(ctx, "p", "r", "s", "o"); err != nil {
+		t.Fatalf("Return.CreateSubmission returned error: %v", err)
+	}
+	submission, _, _, err := client.Return.FetchSubmission(ctx, "p", "r", "s")
+	if err != nil {
+		t.Fatalf("Return.FetchSubmission returned error: %v", err)
+	}
+	if !submission.Final() {
+		t.Errorf("Return.FetchSubmission returned %+v", submission.Attributes)
+	}
+
+	state, err := PaymentStateDelivered.Transition(PaymentStateReturned)
+	if err != nil || state != PaymentStateReturned {
+		t.Errorf("Transition to returned = %v, %v", state, err)
+	}
+}
+
+func TestReturnService_Fetch_NotFound(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/returns/r", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error_message":"record r does not exist"}`)
+	})
+
+	_, _, _, err := client.Return.Fetch(context.Background(), "p", "r")
+	if !IsNotFound(err) {
+		t.Errorf("Return.Fetch returned %v, want a not found error", err)
+	}
+}
@@ -0,0 +1,158 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ReversalService handles the reversals of payments: the sending bank
+// cancels a payment it sent in error, e.g. a duplicate Bacs payment.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-reversals
+type ReversalService service
+
+// ReversalAttributes holds the attributes of a reversal. The reversed
+// payment is identified by the URL, so there are none yet.
+type ReversalAttributes struct{}
+
+type Reversal struct {
+	Attributes     *ReversalAttributes `json:"attributes"`
+	CreatedOn      time.Time           `json:"created_on"`
+	ID             string              `json:"id"`
+	ModifiedOn     time.Time           `json:"modified_on"`
+	OrganisationID string              `json:"organisation_id"`
+	Type           string              `json:"type"`
+	Version        int                 `json:"version"`
+}
+
+type ReversalResponse struct {
+	Data  *Reversal     `json:"data"`
+	Links *PaymentLinks `json:"links"`
+}
+
+type ReversalCreateRequestData struct {
+	Attributes     *ReversalAttributes `json:"attributes"`
+	OrganisationID string              `json:"organisation_id"`
+	ID             string              `json:"id"`
+	Type           string              `json:"type"`
+}
+
+type ReversalCreateRequest struct {
+	Data *ReversalCreateRequestData `json:"data"`
+}
+
+// Create creates a reversal with the given id of the payment paymentID.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-reversals-create
+func (s *ReversalService) Create(ctx context.Context, paymentID string, id string, organizationId string) (*Reversal, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Reversal.Create")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/reversals", paymentID)
+
+	req, err := s.client.NewRequest("POST", u,
+		&ReversalCreateRequest{&ReversalCreateRequestData{
+			&ReversalAttributes{},
+			organizationId,
+			id,
+			"reversals"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *ReversalResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-reversals-fetch
+func (s *ReversalService) Fetch(ctx context.Context, paymentID string, id string) (*Reversal, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Reversal.Fetch")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/reversals/%s", paymentID, id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *ReversalResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// CreateSubmission submits the reversal reversalID to the payment scheme.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-reversals-create-reversal-submission
+func (s *ReversalService) CreateSubmission(ctx context.Context, paymentID string, reversalID string, submissionID string, organizationId string) (*PaymentSubmission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Reversal.CreateSubmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/reversals/%s/submissions", paymentID, reversalID)
+
+	req, err := s.client.NewRequest("POST", u,
+		&PaymentSubmissionCreateRequest{&PaymentSubmissionCreateRequestData{
+			organizationId,
+			submissionID,
+			"reversal_submissions"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentSubmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-reversals-fetch-reversal-submission
+func (s *ReversalService) FetchSubmission(ctx context.Context, paymentID string, reversalID string, submissionID string) (*PaymentSubmission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Reversal.FetchSubmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/reversals/%s/submissions/%s", paymentID, reversalID, submissionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *PaymentSubmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// FetchAdmission fetches the admission of an inbound reversal.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#transaction-payments-reversals-fetch-reversal-admission
+func (s *ReversalService) FetchAdmission(ctx context.Context, paymentID string, reversalID string, admissionID string) (*Admission, *PaymentLinks, *Response, error) {
+	ctx = withOperation(ctx, "Reversal.FetchAdmission")
+
+	u := fmt.Sprintf("/v1/transaction/payments/%s/reversals/%s/admissions/%s", paymentID, reversalID, admissionID)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *AdmissionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
@@ -0,0 +1,61 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestReversalService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/reversals", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var raw map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&raw)
+		if raw["data"]["id"] != "v" || raw["data"]["type"] != "reversals" || raw["data"]["attributes"] == nil {
+			t.Errorf("Request body = %v", raw)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"v","type":"reversals","attributes":{}}}`)
+	})
+	mux.HandleFunc("/v1/transaction/payments/p/reversals/v/admissions/a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id":"a","attributes":{"status":"confirmed"}}}`)
+	})
+
+	ctx := context.Background()
+	reversal, _, _, err := client.Reversal.Create(ctx, "p", "v", "o")
+	if err != nil {
+		t.Fatalf("Reversal.Create returned error: %v", err)
+	}
+	if reversal.ID != "v" {
+		t.Errorf("Reversal.Create returned %+v", reversal)
+	}
+
+	admission, _, _, err := client.Reversal.FetchAdmission(ctx, "p", "v", "a")
+	if err != nil {
+		t.Fatalf("Reversal.FetchAdmission returned error: %v", err)
+	}
+	if admission.Attributes.Status != AdmissionStatusConfirmed {
+		t.Errorf("Reversal.FetchAdmission returned %+v", admission.Attributes)
+	}
+}
+
+func TestReversalService_Create_Conflict(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/transaction/payments/p/reversals", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error_message":"reversal already exists"}`)
+	})
+
+	_, _, _, err := client.Reversal.Create(context.Background(), "p", "v", "o")
+	if !IsConflict(err) {
+		t.Errorf("Reversal.Create returned %v, want a conflict error", err)
+	}
+}
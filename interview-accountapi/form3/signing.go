@@ -0,0 +1,20 @@
+package form3
+
+import (
+	"github.com/vslovik/form3/httpsig"
+)
+
+// WithSigning makes the client sign every request, as the Form3 API
+// requires, with the RSA private key in PEM format registered at the API
+// under keyID. The Date, Digest and Authorization headers of every attempt
+// are set by the signer, see package httpsig.
+func WithSigning(keyID string, privateKeyPEM []byte) ClientOption {
+	return func(c *Client) error {
+		s, err := httpsig.NewSigner(keyID, privateKeyPEM)
+		if err != nil {
+			return err
+		}
+		c.signer = s
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package form3
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"testing"
+
+	"github.com/vslovik/form3/form3test"
+	"github.com/vslovik/form3/httpsig"
+)
+
+func TestWithSigning(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey returned error: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	srv := form3test.NewServer()
+	defer srv.Close()
+	srv.Verify = httpsig.NewVerifier(map[string]*rsa.PublicKey{"key-1": &key.PublicKey}).Verify
+
+	signed, err := NewClient(WithBaseURL(srv.URL), WithSigning("key-1", pemKey))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	ctx := context.Background()
+	if _, _, _, err := signed.Account.Create(ctx, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", &AccountCreateRequestAttributes{
+		BankID:       "400300",
+		BankIDCode:   "GBDSC",
+		Bic:          "NWBKGB22",
+		Country:      "GB",
+		BaseCurrency: "GBP",
+	}); err != nil {
+		t.Errorf("Account.Create of a signed client returned error: %v", err)
+	}
+	if _, err := signed.Account.Delete(ctx, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", 0); err != nil {
+		t.Errorf("Account.Delete of a signed client returned error: %v", err)
+	}
+
+	unsigned, _ := NewClient(WithBaseURL(srv.URL))
+	_, _, resp, err := unsigned.Account.List(ctx, nil)
+	if err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Account.List of an unsigned client returned %v, want 401", err)
+	}
+
+	if _, err := NewClient(WithSigning("key-1", []byte("x"))); err == nil {
+		t.Errorf("NewClient with an invalid key returned no error")
+	}
+}
+
+func TestNewClient_ClientCredentialsAndSigning(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey returned error: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	if _, err := NewClient(WithClientCredentials("id", "secret"), WithSigning("key-1", pemKey)); err == nil {
+		t.Errorf("NewClient with client credentials and signing returned no error")
+	}
+	if _, err := NewClient(WithSigning("key-1", pemKey), WithClientCredentials("id", "secret")); err == nil {
+		t.Errorf("NewClient with signing and client credentials returned no error")
+	}
+}
@@ -0,0 +1,292 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SubscriptionService handles the notification subscriptions of the Form3
+// API. A subscription makes the API notify a callback of the events of a
+// record type, e.g. the creation of payments, instead of being polled.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#subscriptions
+type SubscriptionService service
+
+// Callback transports of a subscription.
+const (
+	CallbackTransportHTTP  = "http"
+	CallbackTransportQueue = "queue"
+)
+
+// Event types of a subscription.
+const (
+	EventTypeCreated = "created"
+	EventTypeUpdated = "updated"
+	EventTypeDeleted = "deleted"
+)
+
+// Record types of a subscription.
+const (
+	RecordTypeAccounts           = "accounts"
+	RecordTypePayments           = "payments"
+	RecordTypePaymentSubmissions = "payment_submissions"
+	RecordTypePaymentAdmissions  = "payment_admissions"
+	RecordTypeReturns            = "returns"
+	RecordTypeReversals          = "reversals"
+	RecordTypeRecalls            = "recalls"
+)
+
+// SubscriptionAttributes holds the attributes of a subscription. CallbackURI
+// is a URL for the http transport and a queue address for the queue
+// transport.
+type SubscriptionAttributes struct {
+	CallbackTransport string `json:"callback_transport"`
+	CallbackURI       string `json:"callback_uri"`
+	Deactivated       bool   `json:"deactivated,omitempty"`
+	EventType         string `json:"event_type"`
+	RecordType        string `json:"record_type"`
+	UserID            string `json:"user_id,omitempty"`
+}
+
+type Subscription struct {
+	Attributes     *SubscriptionAttributes `json:"attributes"`
+	CreatedOn      time.Time               `json:"created_on"`
+	ID             string                  `json:"id"`
+	ModifiedOn     time.Time               `json:"modified_on"`
+	OrganisationID string                  `json:"organisation_id"`
+	Type           string                  `json:"type"`
+	Version        int                     `json:"version"`
+}
+
+// SubscriptionLinks holds the links of a subscription response.
+type SubscriptionLinks struct {
+	Self string `json:"self"`
+}
+
+type SubscriptionResponse struct {
+	Data  *Subscription      `json:"data"`
+	Links *SubscriptionLinks `json:"links"`
+}
+
+type SubscriptionListResponse struct {
+	Data  []*Subscription `json:"data"`
+	Links *ListLinks      `json:"links"`
+}
+
+type SubscriptionCreateRequestData struct {
+	Attributes     *SubscriptionAttributes `json:"attributes"`
+	OrganisationID string                  `json:"organisation_id"`
+	ID             string                  `json:"id"`
+	Type           string                  `json:"type"`
+}
+
+type SubscriptionCreateRequest struct {
+	Data *SubscriptionCreateRequestData `json:"data"`
+}
+
+// SubscriptionUpdateRequestAttributes holds the attributes changed by an
+// update. Nil fields are left unchanged.
+type SubscriptionUpdateRequestAttributes struct {
+	CallbackTransport *string `json:"callback_transport,omitempty"`
+	CallbackURI       *string `json:"callback_uri,omitempty"`
+	Deactivated       *bool   `json:"deactivated,omitempty"`
+	EventType         *string `json:"event_type,omitempty"`
+	RecordType        *string `json:"record_type,omitempty"`
+}
+
+type SubscriptionUpdateRequestData struct {
+	Attributes *SubscriptionUpdateRequestAttributes `json:"attributes"`
+	ID         string                               `json:"id"`
+	Type       string                               `json:"type"`
+	Version    int                                  `json:"version"`
+}
+
+type SubscriptionUpdateRequest struct {
+	Data *SubscriptionUpdateRequestData `json:"data"`
+}
+
+// SubscriptionListOptions specifies the optional parameters to the
+// SubscriptionService.List method.
+type SubscriptionListOptions struct {
+	ListOptions
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#subscriptions-create
+func (s *SubscriptionService) Create(ctx context.Context, id string, organizationId string, attributes *SubscriptionAttributes) (*Subscription, *SubscriptionLinks, *Response, error) {
+	ctx = withOperation(ctx, "Subscription.Create")
+
+	req, err := s.client.NewRequest("POST", "/v1/notification/subscriptions",
+		&SubscriptionCreateRequest{&SubscriptionCreateRequestData{
+			attributes,
+			organizationId,
+			id,
+			"subscriptions"}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *SubscriptionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#subscriptions-fetch
+func (s *SubscriptionService) Fetch(ctx context.Context, id string) (*Subscription, *SubscriptionLinks, *Response, error) {
+	ctx = withOperation(ctx, "Subscription.Fetch")
+
+	u := fmt.Sprintf("/v1/notification/subscriptions/%s", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *SubscriptionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#subscriptions-list
+func (s *SubscriptionService) List(ctx context.Context, opts *SubscriptionListOptions) ([]*Subscription, *ListLinks, *Response, error) {
+	ctx = withOperation(ctx, "Subscription.List")
+
+	u, err := addOptions("/v1/notification/subscriptions", opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return s.list(ctx, u)
+}
+
+// list fetches the page of subscriptions at u.
+func (s *SubscriptionService) list(ctx context.Context, u string) ([]*Subscription, *ListLinks, *Response, error) {
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *SubscriptionListResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+	resp.populatePageValues(r.Links)
+
+	return r.Data, r.Links, resp, nil
+}
+
+// SubscriptionIterator iterates over the subscriptions of a list, fetching
+// pages lazily as they are needed, like AccountIterator.
+type SubscriptionIterator struct {
+	pager
+	page         []*Subscription
+	subscription *Subscription
+}
+
+// ListAll returns an iterator over all subscriptions, starting at the page
+// selected by opts, like AccountService.ListAll.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#subscriptions-list
+func (s *SubscriptionService) ListAll(ctx context.Context, opts *SubscriptionListOptions) *SubscriptionIterator {
+	ctx = withOperation(ctx, "Subscription.ListAll")
+
+	it := &SubscriptionIterator{}
+	it.pager = pager{ctx: ctx, load: func(u string) (int, string, string, string, error) {
+		var (
+			subscriptions []*Subscription
+			links         *ListLinks
+			err           error
+		)
+		if u == "" {
+			subscriptions, links, _, err = s.List(ctx, opts)
+		} else {
+			subscriptions, links, _, err = s.list(ctx, u)
+		}
+		if err != nil {
+			return 0, "", "", "", err
+		}
+		it.page = subscriptions
+		if links == nil {
+			return len(subscriptions), "", "", "", nil
+		}
+		return len(subscriptions), links.Next, links.Self, links.Last, nil
+	}}
+	return it
+}
+
+// Next advances the iterator to the next subscription. It returns false
+// when the iteration stops, either because there are no more subscriptions
+// or because of an error reported by Err.
+func (it *SubscriptionIterator) Next() bool {
+	it.subscription = nil
+	if !it.advance() {
+		return false
+	}
+	it.subscription, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Subscription returns the current subscription. It is only valid after a
+// call to Next that returned true.
+func (it *SubscriptionIterator) Subscription() *Subscription {
+	return it.subscription
+}
+
+// Update changes the attributes set in patch on the subscription with the
+// given id. version must be the current version of the subscription,
+// otherwise the update is rejected with a *ConflictError.
+//
+// Form3 API docs: https://api-docs.form3.tech/api.html#subscriptions-patch
+func (s *SubscriptionService) Update(ctx context.Context, id string, version int, patch *SubscriptionUpdateRequestAttributes) (*Subscription, *SubscriptionLinks, *Response, error) {
+	ctx = withOperation(ctx, "Subscription.Update")
+
+	u := fmt.Sprintf("/v1/notification/subscriptions/%s", id)
+
+	req, err := s.client.NewRequest("PATCH", u,
+		&SubscriptionUpdateRequest{&SubscriptionUpdateRequestData{
+			patch,
+			id,
+			"subscriptions",
+			version}})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var r *SubscriptionResponse
+	resp, err := s.client.Do(ctx, req, &r)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return r.Data, r.Links, resp, nil
+}
+
+// Deactivate stops the notifications of the subscription with the given id
+// without deleting it. They are resumed by an update of Deactivated to false.
+func (s *SubscriptionService) Deactivate(ctx context.Context, id string, version int) (*Subscription, *SubscriptionLinks, *Response, error) {
+	ctx = withOperation(ctx, "Subscription.Deactivate")
+
+	return s.Update(ctx, id, version, &SubscriptionUpdateRequestAttributes{Deactivated: Bool(true)})
+}
+
+// Form3 API docs: https://api-docs.form3.tech/api.html#subscriptions-delete
+func (s *SubscriptionService) Delete(ctx context.Context, id string, version int) (*Response, error) {
+	ctx = withOperation(ctx, "Subscription.Delete")
+
+	u := fmt.Sprintf("/v1/notification/subscriptions/%s?version=%d", id, version)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,157 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const subscriptionJSON = `{
+	"data":{
+		"attributes":{
+			"callback_transport":"http",
+			"callback_uri":"https://example.com/form3/events",
+			"event_type":"created",
+			"record_type":"payments"
+		},
+		"id":"f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1",
+		"organisation_id":"743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb",
+		"type":"subscriptions",
+		"version":0
+	},
+	"links":{"self":"/v1/notification/subscriptions/f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1"}
+}`
+
+func testSubscriptionAttributes() *SubscriptionAttributes {
+	return &SubscriptionAttributes{
+		CallbackTransport: CallbackTransportHTTP,
+		CallbackURI:       "https://example.com/form3/events",
+		EventType:         EventTypeCreated,
+		RecordType:        RecordTypePayments,
+	}
+}
+
+func TestSubscriptionService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/notification/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(SubscriptionCreateRequest)
+		json.NewDecoder(r.Body).Decode(v)
+		want := &SubscriptionCreateRequest{&SubscriptionCreateRequestData{
+			testSubscriptionAttributes(),
+			"743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb",
+			"f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1",
+			"subscriptions",
+		}}
+		if !reflect.DeepEqual(v, want) {
+			t.Errorf("Request body = %+v, want %+v", v.Data, want.Data)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, subscriptionJSON)
+	})
+
+	subscription, links, _, err := client.Subscription.Create(context.Background(),
+		"f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1", "743d5b63-8e6f-432e-a8fa-c5d8d2ee5fcb", testSubscriptionAttributes())
+	if err != nil {
+		t.Fatalf("Subscription.Create returned error: %v", err)
+	}
+	if !reflect.DeepEqual(subscription.Attributes, testSubscriptionAttributes()) {
+		t.Errorf("Subscription.Create returned %+v", subscription.Attributes)
+	}
+	if links.Self != "/v1/notification/subscriptions/f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1" {
+		t.Errorf("Subscription.Create returned links %+v", links)
+	}
+}
+
+func TestSubscriptionService_Fetch(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/notification/subscriptions/f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, subscriptionJSON)
+	})
+
+	subscription, _, _, err := client.Subscription.Fetch(context.Background(), "f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1")
+	if err != nil {
+		t.Fatalf("Subscription.Fetch returned error: %v", err)
+	}
+	if subscription.Attributes.RecordType != RecordTypePayments || subscription.Attributes.Deactivated {
+		t.Errorf("Subscription.Fetch returned %+v", subscription.Attributes)
+	}
+}
+
+func TestSubscriptionService_ListAll(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/notification/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("page[number]") {
+		case "1":
+			fmt.Fprint(w, `{"data":[{"id":"1"}],"links":{"self":"/v1/notification/subscriptions?page%5Bnumber%5D=1"}}`)
+		default:
+			fmt.Fprint(w, `{"data":[{"id":"0"}],"links":{
+				"self":"/v1/notification/subscriptions?page%5Bnumber%5D=0",
+				"next":"/v1/notification/subscriptions?page%5Bnumber%5D=1"}}`)
+		}
+	})
+
+	var ids []string
+	it := client.Subscription.ListAll(context.Background(), nil)
+	for it.Next() {
+		ids = append(ids, it.Subscription().ID)
+	}
+	if it.Err() != nil {
+		t.Fatalf("ListAll returned error: %v", it.Err())
+	}
+	if want := []string{"0", "1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListAll returned %v, want %v", ids, want)
+	}
+}
+
+func TestSubscriptionService_Deactivate(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/notification/subscriptions/f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PATCH")
+		var raw map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&raw)
+		want := map[string]interface{}{"deactivated": true}
+		if !reflect.DeepEqual(raw["data"]["attributes"], want) || raw["data"]["version"] != 2.0 {
+			t.Errorf("Request body = %v", raw)
+		}
+		fmt.Fprint(w, `{"data":{"id":"f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1","version":3,"attributes":{"deactivated":true}}}`)
+	})
+
+	subscription, _, _, err := client.Subscription.Deactivate(context.Background(), "f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1", 2)
+	if err != nil {
+		t.Fatalf("Subscription.Deactivate returned error: %v", err)
+	}
+	if !subscription.Attributes.Deactivated || subscription.Version != 3 {
+		t.Errorf("Subscription.Deactivate returned %+v", subscription)
+	}
+}
+
+func TestSubscriptionService_Delete(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/notification/subscriptions/f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testFormValues(t, r, values{"version": "3"})
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error_message":"record does not exist"}`)
+	})
+
+	_, err := client.Subscription.Delete(context.Background(), "f1b1b1a4-1d7c-4b8e-9a3c-6b51d8f3e2a1", 3)
+	if !IsNotFound(err) {
+		t.Errorf("Subscription.Delete returned %v, want a not found error", err)
+	}
+}
@@ -49,6 +49,38 @@ func reflectValue(values url.Values, val reflect.Value) error {
 			continue
 		}
 
+		// Fields of untagged embedded structs are encoded as if they were
+		// fields of the outer struct.
+		if sf.Anonymous && name == "" {
+			for sv.Kind() == reflect.Ptr && !sv.IsNil() {
+				sv = sv.Elem()
+			}
+			if sv.Kind() == reflect.Ptr {
+				continue
+			}
+			if sv.Kind() == reflect.Struct {
+				if err := reflectValue(values, sv); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if sv.Kind() == reflect.Slice || sv.Kind() == reflect.Array {
+			if opts.Contains("comma") {
+				s := make([]string, sv.Len())
+				for i := range s {
+					s[i] = valueString(sv.Index(i), opts)
+				}
+				values.Add(name, strings.Join(s, ","))
+				continue
+			}
+			for i := 0; i < sv.Len(); i++ {
+				values.Add(name, valueString(sv.Index(i), opts))
+			}
+			continue
+		}
+
 		values.Add(name, valueString(sv, opts))
 	}
 
@@ -120,3 +152,15 @@ func (o tagOptions) Contains(option string) bool {
 	}
 	return false
 }
+
+// String returns a pointer to the string value passed in. It helps to set
+// optional string fields of request attributes.
+func String(v string) *string { return &v }
+
+// Bool returns a pointer to the bool value passed in. It helps to set
+// optional bool fields of request attributes.
+func Bool(v bool) *bool { return &v }
+
+// Int returns a pointer to the int value passed in. It helps to set
+// optional int fields of request attributes.
+func Int(v int) *int { return &v }
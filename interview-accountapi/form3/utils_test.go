@@ -0,0 +1,60 @@
+package form3
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	type inner struct {
+		A string `url:"a,omitempty"`
+	}
+	tests := []struct {
+		in   interface{}
+		want url.Values
+	}{
+		{nil, url.Values{}},
+		{(*ListOptions)(nil), url.Values{}},
+		{&ListOptions{Page: 2, PerPage: 10}, url.Values{"page[number]": {"2"}, "page[size]": {"10"}}},
+		{
+			&AccountListOptions{
+				ListOptions:       ListOptions{PerPage: 10},
+				AccountListFilter: AccountListFilter{Country: []string{"GB", "FR"}, Iban: []string{testIBAN}},
+			},
+			url.Values{
+				"page[size]":      {"10"},
+				"filter[country]": {"GB,FR"},
+				"filter[iban]":    {testIBAN},
+			},
+		},
+		{
+			&struct {
+				*inner
+				B []int `url:"b"`
+			}{&inner{A: "x"}, []int{1, 2}},
+			url.Values{"a": {"x"}, "b": {"1", "2"}},
+		},
+		{
+			&struct {
+				*inner
+			}{},
+			url.Values{},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Values(tt.in)
+		if err != nil {
+			t.Errorf("Values(%+v) returned error: %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Values(%+v) returned %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValues_NotStruct(t *testing.T) {
+	if _, err := Values(3); err == nil {
+		t.Errorf("Values(3) returned no error")
+	}
+}
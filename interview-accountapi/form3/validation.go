@@ -0,0 +1,147 @@
+package form3
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vslovik/form3/iban"
+)
+
+var (
+	bicPattern  = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// FieldError describes an invalid field of a request.
+type FieldError struct {
+	Field  string // JSON name of the field
+	Value  string // rejected value
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Field, e.Reason)
+}
+
+// FieldErrors reports all invalid fields of a request rejected before it
+// was sent. It matches ErrValidation with errors.Is, like the
+// *ValidationError returned when the API rejects a request.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	s := make([]string, len(e))
+	for i, fe := range e {
+		s[i] = fe.Error()
+	}
+	return "invalid request: " + strings.Join(s, "; ")
+}
+
+func (e FieldErrors) Is(target error) bool { return target == ErrValidation }
+
+func (e *FieldErrors) add(field, value, reason string) {
+	*e = append(*e, &FieldError{Field: field, Value: value, Reason: reason})
+}
+
+// has reports whether e holds an error for field.
+func (e FieldErrors) has(field string) bool {
+	for _, fe := range e {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// err returns e as an error, or nil if it is empty.
+func (e FieldErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// WithRequestValidation makes AccountService.Create validate the request
+// with Validate before sending it, so that invalid input is reported without
+// a round-trip to the API.
+func WithRequestValidation() ClientOption {
+	return func(c *Client) error {
+		c.validateRequests = true
+		return nil
+	}
+}
+
+// Validate checks the attributes against the formats documented by the
+// Form3 API and against the rules registered for the country with
+// RegisterCountryRules. It returns FieldErrors listing every invalid field.
+func (a *AccountCreateRequestAttributes) Validate() error {
+	var errs FieldErrors
+	a.validate(&errs)
+	return errs.err()
+}
+
+func (a *AccountCreateRequestAttributes) validate(errs *FieldErrors) {
+	if a == nil {
+		errs.add("attributes", "", "is required")
+		return
+	}
+
+	switch {
+	case a.Country == "":
+		errs.add("country", a.Country, "is required")
+	case !IsCountryCode(a.Country):
+		errs.add("country", a.Country, "is not an ISO 3166-1 alpha-2 country code")
+	default:
+		if r, ok := LookupCountryRules(a.Country); ok {
+			r.check(a, errs)
+		}
+	}
+	if a.BaseCurrency != "" && !IsCurrencyCode(a.BaseCurrency) {
+		errs.add("base_currency", a.BaseCurrency, "is not an ISO 4217 currency code")
+	}
+	if a.Bic != "" && !bicPattern.MatchString(a.Bic) {
+		errs.add("bic", a.Bic, "is not a valid BIC")
+	}
+	if a.Iban != "" {
+		if i, err := iban.Parse(a.Iban); err != nil {
+			errs.add("iban", a.Iban, "is not a valid IBAN")
+		} else if IsCountryCode(a.Country) && i.CountryCode != a.Country && !errs.has("iban") {
+			errs.add("iban", a.Iban, fmt.Sprintf("is not an IBAN of %v", a.Country))
+		}
+	}
+	switch a.AccountClassification {
+	case "", AccountClassificationPersonal, AccountClassificationBusiness:
+	default:
+		errs.add("account_classification", a.AccountClassification,
+			fmt.Sprintf("must be %v or %v", AccountClassificationPersonal, AccountClassificationBusiness))
+	}
+	switch a.Status {
+	case "", AccountStatusPending, AccountStatusConfirmed, AccountStatusFailed, AccountStatusClosed:
+	default:
+		errs.add("status", a.Status, "is not a valid account status")
+	}
+	switch a.ValidationType {
+	case "", AccountValidationTypeCard, AccountValidationTypeNone:
+	default:
+		errs.add("validation_type", a.ValidationType, "is not a valid validation type")
+	}
+	if len(a.Name) > 4 {
+		errs.add("name", strings.Join(a.Name, ","), "must have at most 4 lines")
+	}
+	if len(a.AlternativeNames) > 3 {
+		errs.add("alternative_names", strings.Join(a.AlternativeNames, ","), "must have at most 3 names")
+	}
+}
+
+// validateAccountCreate checks a whole account creation request.
+func validateAccountCreate(id, organisationID string, attributes *AccountCreateRequestAttributes) error {
+	var errs FieldErrors
+	if !uuidPattern.MatchString(id) {
+		errs.add("id", id, "is not a UUID")
+	}
+	if !uuidPattern.MatchString(organisationID) {
+		errs.add("organisation_id", organisationID, "is not a UUID")
+	}
+	attributes.validate(&errs)
+	return errs.err()
+}
@@ -0,0 +1,99 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func validAttributes() *AccountCreateRequestAttributes {
+	return &AccountCreateRequestAttributes{
+		BankID:                "400300",
+		BankIDCode:            "GBDSC",
+		BaseCurrency:          "GBP",
+		Bic:                   "NWBKGB22",
+		Country:               "GB",
+		AccountNumber:         "10000004",
+		Iban:                  "GB71NWBK40030212764204",
+		AccountClassification: AccountClassificationPersonal,
+	}
+}
+
+func TestAccountCreateRequestAttributes_Validate(t *testing.T) {
+	if err := validAttributes().Validate(); err != nil {
+		t.Errorf("Validate returned error: %v", err)
+	}
+
+	tests := []struct {
+		field  string
+		modify func(a *AccountCreateRequestAttributes)
+	}{
+		{"country", func(a *AccountCreateRequestAttributes) { a.Country = "" }},
+		{"country", func(a *AccountCreateRequestAttributes) { a.Country = "x" }},
+		{"country", func(a *AccountCreateRequestAttributes) { a.Country = "XX" }},
+		{"base_currency", func(a *AccountCreateRequestAttributes) { a.BaseCurrency = "x" }},
+		{"bic", func(a *AccountCreateRequestAttributes) { a.Bic = "x" }},
+		{"bic", func(a *AccountCreateRequestAttributes) { a.Bic = "nwbkgb22" }},
+		{"iban", func(a *AccountCreateRequestAttributes) { a.Iban = "x" }},
+		{"iban", func(a *AccountCreateRequestAttributes) { a.Iban = "GB72NWBK40030212764204" }},
+		{"iban", func(a *AccountCreateRequestAttributes) { a.Iban = "DE89370400440532013000" }},
+		{"account_classification", func(a *AccountCreateRequestAttributes) { a.AccountClassification = "x" }},
+		{"status", func(a *AccountCreateRequestAttributes) { a.Status = "x" }},
+		{"validation_type", func(a *AccountCreateRequestAttributes) { a.ValidationType = "x" }},
+		{"name", func(a *AccountCreateRequestAttributes) { a.Name = []string{"a", "b", "c", "d", "e"} }},
+		{"alternative_names", func(a *AccountCreateRequestAttributes) { a.AlternativeNames = []string{"a", "b", "c", "d"} }},
+	}
+	for _, tt := range tests {
+		a := validAttributes()
+		tt.modify(a)
+		err := a.Validate()
+
+		var errs FieldErrors
+		if !errors.As(err, &errs) {
+			t.Errorf("Validate with invalid %v returned %v, want FieldErrors", tt.field, err)
+			continue
+		}
+		if len(errs) != 1 || errs[0].Field != tt.field {
+			t.Errorf("Validate with invalid %v returned %v", tt.field, err)
+		}
+		if !IsValidation(err) {
+			t.Errorf("IsValidation(%v) is false", err)
+		}
+	}
+}
+
+func TestAccountCreateRequestAttributes_ValidateMultipleFields(t *testing.T) {
+	err := (&AccountCreateRequestAttributes{Country: "x", Bic: "x", Iban: "x"}).Validate()
+
+	var got []string
+	for _, fe := range err.(FieldErrors) {
+		got = append(got, fe.Field)
+	}
+	if want := []string{"country", "bic", "iban"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate reported fields %v, want %v", got, want)
+	}
+	if want := "invalid request: country: is not an ISO 3166-1 alpha-2 country code; bic: is not a valid BIC; iban: is not a valid IBAN"; err.Error() != want {
+		t.Errorf("Error() is %q, want %q", err.Error(), want)
+	}
+}
+
+func TestAccountService_Create_WithRequestValidation(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.validateRequests = true
+
+	mux.HandleFunc("/v1/organisation/accounts", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Invalid request was sent to the API")
+	})
+
+	_, _, resp, err := client.Account.Create(context.Background(), "x", "d91afcdb-62d2-4185-b23d-71c98eaab812", validAttributes())
+	if resp != nil {
+		t.Errorf("Account.Create returned response %v, want nil", resp)
+	}
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "id" {
+		t.Errorf("Account.Create returned error %v, want invalid id", err)
+	}
+}
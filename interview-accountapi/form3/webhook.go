@@ -0,0 +1,340 @@
+package form3
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxEventSize is the largest notification body accepted by WebhookHandler.
+const maxEventSize = 1 << 20
+
+// Event is the envelope of a notification sent by the API to the callback
+// of a subscription. Data holds the record the event is about, as returned
+// by the fetch of its record type.
+type Event struct {
+	ID             string          `json:"id"`
+	CreatedOn      time.Time       `json:"created_on"`
+	EventType      string          `json:"event_type"`  // an EventType... value
+	RecordType     string          `json:"record_type"` // a RecordType... value
+	OrganisationID string          `json:"organisation_id"`
+	Version        int             `json:"version"`
+	Data           json.RawMessage `json:"data"`
+}
+
+type AccountCreated struct {
+	*Event
+	Account *Account
+}
+
+type AccountUpdated struct {
+	*Event
+	Account *Account
+}
+
+type AccountDeleted struct {
+	*Event
+	Account *Account
+}
+
+type PaymentCreated struct {
+	*Event
+	Payment *Payment
+}
+
+type PaymentSubmissionCreated struct {
+	*Event
+	Submission *PaymentSubmission
+}
+
+type PaymentSubmissionUpdated struct {
+	*Event
+	Submission *PaymentSubmission
+}
+
+type PaymentAdmissionCreated struct {
+	*Event
+	Admission *Admission
+}
+
+// DecodeEvent returns the typed event of e, e.g. an *AccountCreated for the
+// creation of an account, or e itself if the event has no type.
+func DecodeEvent(e *Event) (interface{}, error) {
+	var (
+		typed  interface{}
+		record interface{}
+	)
+	switch {
+	case e.RecordType == RecordTypeAccounts && e.EventType == EventTypeCreated:
+		ev := &AccountCreated{Event: e, Account: new(Account)}
+		typed, record = ev, ev.Account
+	case e.RecordType == RecordTypeAccounts && e.EventType == EventTypeUpdated:
+		ev := &AccountUpdated{Event: e, Account: new(Account)}
+		typed, record = ev, ev.Account
+	case e.RecordType == RecordTypeAccounts && e.EventType == EventTypeDeleted:
+		ev := &AccountDeleted{Event: e, Account: new(Account)}
+		typed, record = ev, ev.Account
+	case e.RecordType == RecordTypePayments && e.EventType == EventTypeCreated:
+		ev := &PaymentCreated{Event: e, Payment: new(Payment)}
+		typed, record = ev, ev.Payment
+	case e.RecordType == RecordTypePaymentSubmissions && e.EventType == EventTypeCreated:
+		ev := &PaymentSubmissionCreated{Event: e, Submission: new(PaymentSubmission)}
+		typed, record = ev, ev.Submission
+	case e.RecordType == RecordTypePaymentSubmissions && e.EventType == EventTypeUpdated:
+		ev := &PaymentSubmissionUpdated{Event: e, Submission: new(PaymentSubmission)}
+		typed, record = ev, ev.Submission
+	case e.RecordType == RecordTypePaymentAdmissions && e.EventType == EventTypeCreated:
+		ev := &PaymentAdmissionCreated{Event: e, Admission: new(Admission)}
+		typed, record = ev, ev.Admission
+	default:
+		return e, nil
+	}
+	if len(e.Data) == 0 {
+		return nil, fmt.Errorf("%v event %v has no data", e.RecordType, e.ID)
+	}
+	if err := json.Unmarshal(e.Data, record); err != nil {
+		return nil, fmt.Errorf("%v event %v: %w", e.RecordType, e.ID, err)
+	}
+	return typed, nil
+}
+
+// ErrEventInProgress is returned by Deduplicator.Begin for an event that is
+// being handled.
+var ErrEventInProgress = errors.New("form3: event is being handled")
+
+// Deduplicator records the IDs of the events handled by a WebhookHandler, so
+// that an event delivered more than once is handled once.
+type Deduplicator interface {
+	// Begin reports whether the event id is new, and if so marks it as being
+	// handled. It returns ErrEventInProgress if it is being handled.
+	Begin(id string) (bool, error)
+
+	// End marks the event id as handled, or forgets it if it was not
+	// handled, so that its next delivery is handled again.
+	End(id string, handled bool)
+}
+
+// DefaultDeduplicatorSize is the number of event IDs remembered by the
+// deduplicator of a WebhookHandler without one.
+const DefaultDeduplicatorSize = 10000
+
+// NewMemoryDeduplicator returns a Deduplicator remembering the last size
+// handled event IDs in memory. It is lost on restart and not shared between
+// replicas; a Deduplicator backed by a database should be used for those.
+func NewMemoryDeduplicator(size int) Deduplicator {
+	if size <= 0 {
+		size = DefaultDeduplicatorSize
+	}
+	return &memoryDeduplicator{size: size, handled: make(map[string]*list.Element), inProgress: make(map[string]bool)}
+}
+
+type memoryDeduplicator struct {
+	size int
+
+	mu         sync.Mutex
+	handled    map[string]*list.Element
+	order      list.List // of handled IDs, oldest first
+	inProgress map[string]bool
+}
+
+func (d *memoryDeduplicator) Begin(id string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.handled[id]; ok {
+		return false, nil
+	}
+	if d.inProgress[id] {
+		return false, ErrEventInProgress
+	}
+	d.inProgress[id] = true
+	return true, nil
+}
+
+func (d *memoryDeduplicator) End(id string, handled bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inProgress, id)
+	if !handled {
+		return
+	}
+	d.handled[id] = d.order.PushBack(id)
+	if d.order.Len() > d.size {
+		oldest := d.order.Front()
+		d.order.Remove(oldest)
+		delete(d.handled, oldest.Value.(string))
+	}
+}
+
+// WebhookHandler is an http.Handler receiving the notifications sent by the
+// API to the callback of a subscription. It verifies the signature of every
+// notification, decodes it with DecodeEvent and dispatches it to the On...
+// function of its type, or to OnEvent if it has none.
+//
+// Its responses tell the API whether to deliver a notification again:
+//
+//   - 2xx: the event was handled, had already been handled, or has no
+//     handler, and must not be delivered again.
+//   - 4xx: the notification is invalid, e.g. its signature, and will not
+//     become valid if delivered again.
+//   - 5xx: the event could not be handled, e.g. a handler returned an
+//     error, and must be delivered again.
+type WebhookHandler struct {
+	// Verify checks the signature of a notification, typically the Verify
+	// method of an httpsig.Verifier holding the public key of the API. It is
+	// required: notifications are rejected if it is nil.
+	Verify func(*http.Request) error
+
+	// Dedup records the handled events. It defaults to a memory
+	// deduplicator of DefaultDeduplicatorSize events.
+	Dedup Deduplicator
+
+	OnAccountCreated           func(context.Context, *AccountCreated) error
+	OnAccountUpdated           func(context.Context, *AccountUpdated) error
+	OnAccountDeleted           func(context.Context, *AccountDeleted) error
+	OnPaymentCreated           func(context.Context, *PaymentCreated) error
+	OnPaymentSubmissionCreated func(context.Context, *PaymentSubmissionCreated) error
+	OnPaymentSubmissionUpdated func(context.Context, *PaymentSubmissionUpdated) error
+	OnPaymentAdmissionCreated  func(context.Context, *PaymentAdmissionCreated) error
+
+	// OnEvent handles the events without a typed handler.
+	OnEvent func(context.Context, *Event) error
+
+	// OnError is called with the errors of Dedup and of the handlers, if not
+	// nil, e.g. to log them. They are not sent in the response, which only
+	// tells the API to deliver the event again.
+	OnError func(*http.Request, error)
+
+	once sync.Once
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Verify == nil {
+		http.Error(w, "signature verification is not configured", http.StatusInternalServerError)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxEventSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxEventSize {
+		http.Error(w, "event too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err := h.Verify(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	e := new(Event)
+	if err := json.Unmarshal(body, e); err != nil {
+		http.Error(w, "invalid event: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if e.ID == "" {
+		http.Error(w, "invalid event: no id", http.StatusBadRequest)
+		return
+	}
+	typed, err := DecodeEvent(e)
+	if err != nil {
+		http.Error(w, "invalid event: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.once.Do(func() {
+		if h.Dedup == nil {
+			h.Dedup = NewMemoryDeduplicator(0)
+		}
+	})
+	ok, err := h.Dedup.Begin(e.ID)
+	if err != nil {
+		h.onError(r, err)
+		http.Error(w, "event not handled", http.StatusServiceUnavailable)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = h.dispatch(r.Context(), typed)
+	h.Dedup.End(e.ID, err == nil)
+	if err != nil {
+		h.onError(r, err)
+		http.Error(w, "event not handled", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// onError reports err to OnError, if any.
+func (h *WebhookHandler) onError(r *http.Request, err error) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+}
+
+// dispatch calls the handler of the typed event ev, if any.
+func (h *WebhookHandler) dispatch(ctx context.Context, ev interface{}) error {
+	switch ev := ev.(type) {
+	case *AccountCreated:
+		if h.OnAccountCreated != nil {
+			return h.OnAccountCreated(ctx, ev)
+		}
+		return h.onEvent(ctx, ev.Event)
+	case *AccountUpdated:
+		if h.OnAccountUpdated != nil {
+			return h.OnAccountUpdated(ctx, ev)
+		}
+		return h.onEvent(ctx, ev.Event)
+	case *AccountDeleted:
+		if h.OnAccountDeleted != nil {
+			return h.OnAccountDeleted(ctx, ev)
+		}
+		return h.onEvent(ctx, ev.Event)
+	case *PaymentCreated:
+		if h.OnPaymentCreated != nil {
+			return h.OnPaymentCreated(ctx, ev)
+		}
+		return h.onEvent(ctx, ev.Event)
+	case *PaymentSubmissionCreated:
+		if h.OnPaymentSubmissionCreated != nil {
+			return h.OnPaymentSubmissionCreated(ctx, ev)
+		}
+		return h.onEvent(ctx, ev.Event)
+	case *PaymentSubmissionUpdated:
+		if h.OnPaymentSubmissionUpdated != nil {
+			return h.OnPaymentSubmissionUpdated(ctx, ev)
+		}
+		return h.onEvent(ctx, ev.Event)
+	case *PaymentAdmissionCreated:
+		if h.OnPaymentAdmissionCreated != nil {
+			return h.OnPaymentAdmissionCreated(ctx, ev)
+		}
+		return h.onEvent(ctx, ev.Event)
+	case *Event:
+		return h.onEvent(ctx, ev)
+	}
+	return nil
+}
+
+func (h *WebhookHandler) onEvent(ctx context.Context, e *Event) error {
+	if h.OnEvent == nil {
+		return nil
+	}
+	return h.OnEvent(ctx, e)
+}
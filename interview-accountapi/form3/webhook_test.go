@@ -0,0 +1,211 @@
+package form3
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vslovik/form3/httpsig"
+)
+
+const accountCreatedJSON = `{
+	"id":"0f1c2c1e-5b9b-4a36-8f3a-0b7d9a4f8e11",
+	"event_type":"created",
+	"record_type":"accounts",
+	"organisation_id":"eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+	"version":0,
+	"data":{
+		"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		"organisation_id":"eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		"type":"accounts",
+		"attributes":{"country":"GB","bank_id":"400300","bic":"NWBKGB22"}
+	}
+}`
+
+// testWebhook returns a handler verifying signatures made with the returned
+// signer.
+func testWebhook(t *testing.T) (*WebhookHandler, *httpsig.Signer) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey returned error: %v", err)
+	}
+	h := &WebhookHandler{
+		Verify: httpsig.NewVerifier(map[string]*rsa.PublicKey{"form3": &key.PublicKey}).Verify,
+	}
+	return h, &httpsig.Signer{KeyID: "form3", Key: key}
+}
+
+func deliver(t *testing.T, h http.Handler, s *httpsig.Signer, body string) int {
+	return deliverRecorded(t, h, s, body).Code
+}
+
+// deliverRecorded is deliver, returning the whole response.
+func deliverRecorded(t *testing.T, h http.Handler, s *httpsig.Signer, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "https://example.com/form3/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if s != nil {
+		if err := s.Sign(req); err != nil {
+			t.Fatalf("Sign returned error: %v", err)
+		}
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookHandler_Dispatch(t *testing.T) {
+	h, s := testWebhook(t)
+	var got *AccountCreated
+	h.OnAccountCreated = func(ctx context.Context, e *AccountCreated) error {
+		got = e
+		return nil
+	}
+
+	if code := deliver(t, h, s, accountCreatedJSON); code != http.StatusOK {
+		t.Fatalf("Delivery answered %v, want 200", code)
+	}
+	if got == nil {
+		t.Fatal("OnAccountCreated was not called")
+	}
+	if got.ID != "0f1c2c1e-5b9b-4a36-8f3a-0b7d9a4f8e11" || got.Account.ID != "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc" ||
+		got.Account.Attributes.Bic != "NWBKGB22" {
+		t.Errorf("OnAccountCreated got %+v, account %+v", got.Event, got.Account)
+	}
+}
+
+func TestWebhookHandler_Dedup(t *testing.T) {
+	h, s := testWebhook(t)
+	calls := 0
+	fail := true
+	h.OnAccountCreated = func(ctx context.Context, e *AccountCreated) error {
+		calls++
+		if fail {
+			return errors.New("database is down")
+		}
+		return nil
+	}
+
+	if code := deliver(t, h, s, accountCreatedJSON); code != http.StatusInternalServerError {
+		t.Errorf("Failed delivery answered %v, want 500", code)
+	}
+	fail = false
+	for i := 0; i < 2; i++ {
+		if code := deliver(t, h, s, accountCreatedJSON); code != http.StatusOK {
+			t.Errorf("Delivery %d answered %v, want 200", i, code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("OnAccountCreated called %d times, want 2", calls)
+	}
+}
+
+func TestWebhookHandler_Error(t *testing.T) {
+	h, s := testWebhook(t)
+	h.OnAccountCreated = func(ctx context.Context, e *AccountCreated) error {
+		return errors.New("database password rejected")
+	}
+	var reported []error
+	h.OnError = func(r *http.Request, err error) {
+		reported = append(reported, err)
+	}
+
+	w := deliverRecorded(t, h, s, accountCreatedJSON)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Failed delivery answered %v, want 500", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "password") {
+		t.Errorf("Failed delivery answered %q, leaking the handler error", w.Body.String())
+	}
+	if len(reported) != 1 || reported[0].Error() != "database password rejected" {
+		t.Errorf("OnError got %v, want the handler error", reported)
+	}
+}
+
+func TestWebhookHandler_Reject(t *testing.T) {
+	h, s := testWebhook(t)
+	h.OnEvent = func(ctx context.Context, e *Event) error {
+		t.Errorf("OnEvent called for %+v", e)
+		return nil
+	}
+
+	tests := []struct {
+		name   string
+		signer *httpsig.Signer
+		body   string
+		want   int
+	}{
+		{"unsigned", nil, accountCreatedJSON, http.StatusUnauthorized},
+		{"malformed", s, `{"id":`, http.StatusBadRequest},
+		{"no id", s, `{"event_type":"created","record_type":"payments"}`, http.StatusBadRequest},
+		{"bad data", s, `{"id":"1","event_type":"created","record_type":"accounts","data":[]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if code := deliver(t, h, tt.signer, tt.body); code != tt.want {
+			t.Errorf("%s delivery answered %v, want %v", tt.name, code, tt.want)
+		}
+	}
+
+	// A handler without a verifier accepts nothing.
+	if code := deliver(t, &WebhookHandler{}, s, accountCreatedJSON); code != http.StatusInternalServerError {
+		t.Errorf("Delivery without a verifier answered %v, want 500", code)
+	}
+}
+
+func TestWebhookHandler_Unhandled(t *testing.T) {
+	h, s := testWebhook(t)
+	var got *Event
+	h.OnEvent = func(ctx context.Context, e *Event) error {
+		got = e
+		return nil
+	}
+
+	body := `{"id":"2","event_type":"created","record_type":"mandates","data":{"id":"m"}}`
+	if code := deliver(t, h, s, body); code != http.StatusOK {
+		t.Errorf("Delivery answered %v, want 200", code)
+	}
+	if got == nil || got.RecordType != "mandates" || string(got.Data) != `{"id":"m"}` {
+		t.Errorf("OnEvent got %+v", got)
+	}
+
+	// Events without any handler are acknowledged.
+	h.OnEvent = nil
+	body = `{"id":"3","event_type":"updated","record_type":"payment_submissions","data":{"id":"s"}}`
+	if code := deliver(t, h, s, body); code != http.StatusOK {
+		t.Errorf("Delivery answered %v, want 200", code)
+	}
+}
+
+func TestMemoryDeduplicator(t *testing.T) {
+	d := NewMemoryDeduplicator(2)
+
+	if ok, err := d.Begin("a"); !ok || err != nil {
+		t.Fatalf("Begin(a) = %v, %v", ok, err)
+	}
+	if _, err := d.Begin("a"); err != ErrEventInProgress {
+		t.Errorf("Begin(a) in progress returned %v, want %v", err, ErrEventInProgress)
+	}
+	d.End("a", true)
+	if ok, err := d.Begin("a"); ok || err != nil {
+		t.Errorf("Begin(a) handled = %v, %v", ok, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"b", "c"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			d.Begin(id)
+			d.End(id, true)
+		}(id)
+	}
+	wg.Wait()
+	if ok, _ := d.Begin("a"); !ok {
+		t.Errorf("Begin(a) = false after a was evicted")
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"crypto/rand"
 	"fmt"
 	"github.com/vslovik/form3/form3"
+	"github.com/vslovik/form3/form3/form3test"
+	"github.com/vslovik/form3/form3/iban"
 	"log"
+	"os"
 	"strings"
 	"testing"
 )
@@ -15,6 +18,18 @@ const (
 	organizationId = "d91afcdb-62d2-4185-b23d-71c98eaab812"
 )
 
+// testIBAN is the IBAN of the test accounts, with their bank ID and account
+// number.
+var testIBAN = generateIBAN("GB", "NWBK400300", "10000004")
+
+func generateIBAN(country, bankID, accountNumber string) string {
+	s, err := iban.Generate(country, bankID, accountNumber)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 var attr = &form3.AccountCreateRequestAttributes{
 	BankID:                  "400300",
 	BankIDCode:              "GBDSC",
@@ -23,16 +38,38 @@ var attr = &form3.AccountCreateRequestAttributes{
 	Country:                 "GB",
 	AccountNumber:           "10000004",
 	CustomerID:              "234",
-	Iban:                    "GB28NWBK40030212764204",
+	Iban:                    testIBAN,
 	AccountClassification:   "Personal",
-	JointAccount:            true,
+	JointAccount:            form3.Bool(true),
 	Switched:                "X",
 	SecondaryIdentification: "X",
-	AccountMatchingOptOut:   false,
-	AlternativeNames:        false,
+	AccountMatchingOptOut:   form3.Bool(false),
 }
 
-var client = form3.NewClient(nil)
+var client *form3.Client
+
+// TestMain runs the tests against the API at FORM3_BASE_URL, or against the
+// in-process fake API of form3test when it is not set.
+func TestMain(m *testing.M) {
+	var opts []form3.ClientOption
+	var srv *form3test.Server
+	if os.Getenv(form3.EnvBaseURL) == "" {
+		srv = form3test.NewServer()
+		opts = append(opts, form3.WithBaseURL(srv.URL))
+	}
+
+	var err error
+	client, err = form3.NewClientFromEnv(opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code := m.Run()
+	if srv != nil {
+		srv.Close()
+	}
+	os.Exit(code)
+}
 
 func uuid() string {
 	b := make([]byte, 16)
@@ -97,7 +134,7 @@ func createAccount(t *testing.T, id string, check bool) {
 		if acc.Attributes.CustomerID != "234" {
 			t.Fatalf("Invalid account CustomerID: %v\n", acc.Attributes.CustomerID)
 		}
-		if acc.Attributes.Iban != "GB28NWBK40030212764204" {
+		if acc.Attributes.Iban != testIBAN {
 			t.Fatalf("Invalid account Iban: %v\n", acc.Attributes.Iban)
 		}
 		if acc.Attributes.AccountClassification != "Personal" {
@@ -144,7 +181,7 @@ func createAccountBunch(t *testing.T, number int) {
 	}
 }
 
-func getPage(t *testing.T, page int, opt *form3.ListOptions) []*form3.Account {
+func getPage(t *testing.T, page int, opt *form3.AccountListOptions) []*form3.Account {
 	opt.Page = page
 	accounts, _, _, err := client.Account.List(context.Background(), opt)
 	for _, elem := range accounts {
@@ -159,7 +196,7 @@ func getPage(t *testing.T, page int, opt *form3.ListOptions) []*form3.Account {
 // get all pages of results
 func getAllPages(t *testing.T, perPage int) []*form3.Account {
 	var allAccounts []*form3.Account
-	opt := &form3.ListOptions{PerPage: perPage}
+	opt := &form3.AccountListOptions{ListOptions: form3.ListOptions{PerPage: perPage}}
 	i := 0
 	for {
 		fmt.Printf("Retrieving Page %v of %v accounts...\n", i, opt.PerPage)